@@ -0,0 +1,130 @@
+// Package ansi splits a stream of bytes into text and the control functions defined by ECMA-48.
+//
+// The Tokenizer follows the state machine of DEC's VT500 series terminals described at
+// https://vt100.net/emu/dec_ansi_parser, with the extensions of modern terminals: colons separate
+// subparameters of control sequences, OSC strings can be terminated by BEL, and C1 controls are encoded
+// in UTF-8 as U+0080 to U+009F.
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Kind is the type of a token.
+type Kind int
+
+// Kinds of tokens returned by a Tokenizer.
+const (
+	// Text is a run of printable characters.
+	Text Kind = iota + 1
+	// Control is a single C0 or C1 control character such as "\n" or BEL.
+	Control
+	// Escape is an escape sequence such as "ESC 7" or "ESC ( B".
+	Escape
+	// CSI is a control sequence such as "CSI 1 ; 31 m".
+	CSI
+	// OSC is an operating system command such as "OSC 0 ; title ST".
+	OSC
+	// DCS is a device control string such as "DCS $ q m ST".
+	DCS
+	// SOS is a start of string control string.
+	SOS
+	// PM is a privacy message control string.
+	PM
+	// APC is an application program command control string.
+	APC
+	// Invalid is a malformed or incomplete sequence that terminals ignore.
+	Invalid
+)
+
+// String returns the name of the kind.
+func (k Kind) String() string {
+	switch k {
+	case Text:
+		return "text"
+	case Control:
+		return "control"
+	case Escape:
+		return "escape"
+	case CSI:
+		return "csi"
+	case OSC:
+		return "osc"
+	case DCS:
+		return "dcs"
+	case SOS:
+		return "sos"
+	case PM:
+		return "pm"
+	case APC:
+		return "apc"
+	case Invalid:
+		return "invalid"
+	default:
+		return "unknown"
+	}
+}
+
+// Default is the value of a parameter or subparameter that was omitted, such as the first parameter of "CSI ; 5 H".
+const Default = -1
+
+// Param is a parameter of a control sequence followed by its colon separated subparameters.
+// For example, "38:2::25:255:203" is Param{38, 2, Default, 25, 255, 203}.
+type Param []int
+
+// Value returns the value of the parameter, or def if it was omitted.
+func (p Param) Value(def int) int {
+	if len(p) == 0 || p[0] == Default {
+		return def
+	}
+	return p[0]
+}
+
+// String returns the parameter as it's written in a control sequence.
+func (p Param) String() string {
+	subs := make([]string, len(p))
+	for i, v := range p {
+		if v != Default {
+			subs[i] = strconv.Itoa(v)
+		}
+	}
+	return strings.Join(subs, ":")
+}
+
+// Token is a piece of the stream.
+type Token struct {
+	Kind Kind
+	// Raw holds the bytes of the token as they appeared in the stream.
+	// The Raw bytes of all the tokens hold every byte of the stream, but control characters found in the middle of
+	// a sequence come before it, so writing them reproduces the stream only if no sequence was interrupted.
+	Raw []byte
+
+	// Control is the control character of a Control token, between 0x00 and 0x9f.
+	Control rune
+
+	// Prefix is the private parameter marker of a CSI or DCS token: '<', '=', '>', '?' or 0 if there is none.
+	Prefix byte
+	// Params holds the parameters of a CSI or DCS token.
+	Params []Param
+	// Intermediates holds the intermediate bytes of an Escape, CSI or DCS token, between 0x20 and 0x2f.
+	Intermediates []byte
+	// Final is the final byte of an Escape, CSI or DCS token.
+	Final byte
+
+	// Data holds the content of an OSC, DCS, SOS, PM or APC token, without its introducer and terminator.
+	Data []byte
+}
+
+// Param returns the value of the i-th parameter, or def if it was omitted.
+func (t Token) Param(i, def int) int {
+	if i >= len(t.Params) {
+		return def
+	}
+	return t.Params[i].Value(def)
+}
+
+// String returns the raw bytes of the token.
+func (t Token) String() string {
+	return string(t.Raw)
+}
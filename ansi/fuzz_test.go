@@ -0,0 +1,54 @@
+// +build go1.18
+
+package ansi
+
+import (
+	"testing"
+)
+
+func FuzzTokenizer(f *testing.F) {
+	seeds := []string{
+		"hello\r\n",
+		"\x1b[1;38:2::25:255:203m\x1b[0m",
+		"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\a",
+		"\x1bP1$r0;48:2::1:2:3m\x1b\\",
+		"\x1b_Gf=100\x1b\\\u009b?25h\u0090q\u009c",
+		"\x1b[\x18\x1b(B\xc2\xc2\x9b",
+	}
+	for _, seed := range seeds {
+		f.Add([]byte(seed), uint(len(seed)/2))
+	}
+	f.Fuzz(func(t *testing.T, input []byte, split uint) {
+		tokens := Tokenize(input)
+		// Control characters inside sequences are returned first, so tokens only preserve the bytes of the input.
+		var n int
+		for _, tok := range tokens {
+			if len(tok.Raw) == 0 {
+				t.Fatalf("unexpected empty %v token", tok.Kind)
+			}
+			n += len(tok.Raw)
+		}
+		if n != len(input) {
+			t.Fatalf("expected %d raw bytes, got %d", len(input), n)
+		}
+
+		// Splitting the input in two chunks produces the same sequences.
+		i := int(split % uint(len(input)+1))
+		var tokenizer Tokenizer
+		chunked := append(tokenizer.Feed(input[:i]), tokenizer.Feed(input[i:])...)
+		chunked = append(chunked, tokenizer.Flush()...)
+		if got, wanted := withoutText(chunked), withoutText(tokens); len(got) != len(wanted) {
+			t.Fatalf("expected %d sequences, got %d", len(wanted), len(got))
+		}
+	})
+}
+
+func withoutText(tokens []Token) []Token {
+	var seqs []Token
+	for _, tok := range tokens {
+		if tok.Kind != Text {
+			seqs = append(seqs, tok)
+		}
+	}
+	return seqs
+}
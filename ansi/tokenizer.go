@@ -0,0 +1,508 @@
+package ansi
+
+import (
+	"unicode/utf8"
+)
+
+// Limits that protect against sequences that would never end.
+const (
+	// maxParams is the number of parameters after which a control sequence is ignored.
+	maxParams = 32
+	// maxParamValue is the value at which parameters are capped.
+	maxParamValue = 1<<16 - 1
+	// maxSequenceLen is the number of bytes after which a sequence or control string that isn't terminated yet is
+	// returned as an Invalid token, so that an unterminated string doesn't hold the rest of the stream.
+	maxSequenceLen = 1 << 16
+)
+
+// state is a state of the VT500 parser.
+type state int
+
+const (
+	stateGround state = iota
+	stateEscape
+	stateEscapeIntermediate
+	stateCSIEntry
+	stateCSIParam
+	stateCSIIntermediate
+	stateCSIIgnore
+	stateDCSEntry
+	stateDCSParam
+	stateDCSIntermediate
+	stateDCSPassthrough
+	stateDCSIgnore
+	stateOSCString
+	stateSOSPMAPCString
+)
+
+// high represents a byte above 0x7f that isn't part of a UTF-8 encoded C1 control.
+const high = 0x100
+
+// Tokenizer splits a stream into tokens. Input can be fed in chunks of any size: sequences and UTF-8 encoded
+// characters split across chunks are reassembled.
+//
+// Like in a terminal, control characters found in the middle of an escape or control sequence are returned
+// as Control tokens before the sequence they interrupt. An escape character ends a control string: a string
+// terminated by "ESC \" includes the terminator while a string interrupted by another sequence doesn't.
+// Sequences and strings longer than 64 KiB are returned as Invalid tokens, and the bytes that follow are read as
+// text.
+//
+// The zero value is ready to use.
+type Tokenizer struct {
+	state state
+	// kind is the kind of the control string being read.
+	kind Kind
+	// text holds the text run being read.
+	text []byte
+	// raw holds the bytes of the sequence being read.
+	raw []byte
+	// c2 is true if the last byte was 0xc2, which may introduce a UTF-8 encoded C1 control.
+	c2 bool
+	// stringEscape is true if the last byte of a control string was ESC.
+	stringEscape bool
+
+	prefix        byte
+	params        []byte
+	nparams       int
+	intermediates []byte
+	final         byte
+	data          []byte
+
+	tokens []Token
+}
+
+// Tokenize returns the tokens of b.
+func Tokenize(b []byte) []Token {
+	var t Tokenizer
+	return append(t.Feed(b), t.Flush()...)
+}
+
+// Feed returns the tokens completed by b.
+// The text read so far is returned, except for a trailing incomplete UTF-8 character, while an incomplete
+// sequence is held until it's completed by the next calls to Feed or returned by Flush.
+// The returned tokens don't retain b.
+func (t *Tokenizer) Feed(b []byte) []Token {
+	t.tokens = nil
+	for _, c := range b {
+		t.feed(c)
+		if len(t.raw) > maxSequenceLen {
+			t.abort()
+		}
+	}
+	t.emitText(false)
+	return t.tokens
+}
+
+// Flush returns the text and incomplete sequence held by the tokenizer, and resets it.
+// An incomplete sequence is returned as an Invalid token.
+func (t *Tokenizer) Flush() []Token {
+	t.tokens = nil
+	if t.c2 {
+		t.c2 = false
+		t.advance(high, 0xc2)
+	}
+	t.emitText(true)
+	t.abort()
+	return t.tokens
+}
+
+// feed decodes UTF-8 encoded C1 controls before advancing the state machine.
+func (t *Tokenizer) feed(b byte) {
+	if t.c2 {
+		t.c2 = false
+		if b >= 0x80 && b <= 0x9f {
+			t.advance(int(b), b)
+			return
+		}
+		t.advance(high, 0xc2)
+	}
+	switch {
+	case b == 0xc2:
+		t.c2 = true
+	case b >= 0x80:
+		t.advance(high, b)
+	default:
+		t.advance(int(b), b)
+	}
+}
+
+// appendRaw appends the bytes that encode the character c to dst.
+// The byte b is the encoded value of characters other than C1 controls.
+func appendRaw(dst []byte, c int, b byte) []byte {
+	if c >= 0x80 && c <= 0x9f {
+		return append(dst, 0xc2, b)
+	}
+	return append(dst, b)
+}
+
+// advance moves the state machine with the character c.
+func (t *Tokenizer) advance(c int, b byte) {
+	if t.stringEscape {
+		t.stringEscape = false
+		if c == '\\' {
+			t.raw = append(t.raw, b)
+			t.emitString()
+			return
+		}
+		// The escape character ends the string and starts a new sequence.
+		t.raw = t.raw[:len(t.raw)-1]
+		t.emitString()
+		t.enter(stateEscape, 0x1b, 0x1b)
+	}
+
+	// Transitions from any state.
+	switch {
+	case c == 0x18, c == 0x1a:
+		// CAN and SUB cancel the sequence.
+		t.emitText(true)
+		t.abort()
+		t.emitControl(c, b)
+		return
+	case c == 0x1b:
+		if t.inString() {
+			t.raw = append(t.raw, b)
+			t.stringEscape = true
+			return
+		}
+		t.emitText(true)
+		t.abort()
+		t.enter(stateEscape, c, b)
+		return
+	case c == 0x9c:
+		// ST terminates control strings.
+		if t.inString() {
+			t.raw = appendRaw(t.raw, c, b)
+			t.emitString()
+			return
+		}
+		t.emitText(true)
+		t.abort()
+		t.emitControl(c, b)
+		return
+	case c == 0x90, c == 0x98, c == 0x9b, c == 0x9d, c == 0x9e, c == 0x9f:
+		t.emitText(true)
+		t.abort()
+		t.introduce(c, b)
+		return
+	case c >= 0x80 && c <= 0x9f:
+		t.emitText(true)
+		t.abort()
+		t.emitControl(c, b)
+		return
+	}
+
+	switch t.state {
+	case stateGround:
+		if isC0(c) {
+			t.emitText(true)
+			t.emitControl(c, b)
+			return
+		}
+		t.text = append(t.text, b)
+	case stateEscape:
+		switch {
+		case isC0(c):
+			t.emitControl(c, b)
+		case c == 0x7f:
+			t.raw = append(t.raw, b)
+		case c >= 0x20 && c <= 0x2f:
+			t.raw = append(t.raw, b)
+			t.intermediates = append(t.intermediates, b)
+			t.state = stateEscapeIntermediate
+		case c == '[', c == ']', c == 'P', c == 'X', c == '^', c == '_':
+			// 7-bit forms of the C1 controls that introduce sequences and strings.
+			t.raw = append(t.raw, b)
+			t.introduce(c+0x40, b)
+		case c <= 0x7e:
+			t.raw = append(t.raw, b)
+			t.final = b
+			t.emitSequence(Escape)
+		default:
+			t.raw = append(t.raw, b)
+			t.emitSequence(Invalid)
+		}
+	case stateEscapeIntermediate:
+		switch {
+		case isC0(c):
+			t.emitControl(c, b)
+		case c == 0x7f:
+			t.raw = append(t.raw, b)
+		case c >= 0x20 && c <= 0x2f:
+			t.raw = append(t.raw, b)
+			t.intermediates = append(t.intermediates, b)
+		case c <= 0x7e:
+			t.raw = append(t.raw, b)
+			t.final = b
+			t.emitSequence(Escape)
+		default:
+			t.raw = append(t.raw, b)
+			t.emitSequence(Invalid)
+		}
+	case stateCSIEntry, stateCSIParam, stateCSIIntermediate:
+		if isC0(c) {
+			t.emitControl(c, b)
+			return
+		}
+		t.raw = append(t.raw, b)
+		if t.header(c, b, stateCSIParam, stateCSIIntermediate, stateCSIIgnore) {
+			t.final = b
+			t.emitSequence(CSI)
+		}
+	case stateCSIIgnore:
+		if isC0(c) {
+			t.emitControl(c, b)
+			return
+		}
+		t.raw = append(t.raw, b)
+		if c >= 0x40 && c <= 0x7e {
+			t.emitSequence(Invalid)
+		}
+	case stateDCSEntry, stateDCSParam, stateDCSIntermediate:
+		t.raw = append(t.raw, b)
+		if isC0(c) {
+			return
+		}
+		if t.header(c, b, stateDCSParam, stateDCSIntermediate, stateDCSIgnore) {
+			t.final = b
+			t.state = stateDCSPassthrough
+		}
+	case stateDCSPassthrough:
+		t.raw = append(t.raw, b)
+		if c != 0x7f {
+			t.data = append(t.data, b)
+		}
+	case stateDCSIgnore:
+		t.raw = append(t.raw, b)
+	case stateOSCString:
+		if c == 0x07 {
+			// xterm accepts BEL as the terminator of OSC strings.
+			t.raw = append(t.raw, b)
+			t.emitString()
+			return
+		}
+		t.raw = append(t.raw, b)
+		if !isC0(c) {
+			t.data = append(t.data, b)
+		}
+	case stateSOSPMAPCString:
+		t.raw = append(t.raw, b)
+		if !isC0(c) {
+			t.data = append(t.data, b)
+		}
+	}
+}
+
+// header reads a character of the parameters, intermediates or final byte of a CSI or DCS sequence.
+// It returns true if c is the final byte.
+func (t *Tokenizer) header(c int, b byte, param, intermediate, ignore state) bool {
+	entry := t.state == stateCSIEntry || t.state == stateDCSEntry
+	switch {
+	case c == 0x7f:
+	case c >= 0x30 && c <= 0x3b && t.state != intermediate:
+		if c == ';' {
+			t.nparams++
+		}
+		if t.nparams >= maxParams {
+			t.state = ignore
+			return false
+		}
+		t.params = append(t.params, b)
+		t.state = param
+	case c >= 0x3c && c <= 0x3f && entry:
+		t.prefix = b
+		t.state = param
+	case c >= 0x20 && c <= 0x2f:
+		t.intermediates = append(t.intermediates, b)
+		t.state = intermediate
+	case c >= 0x40 && c <= 0x7e:
+		return true
+	default:
+		t.state = ignore
+	}
+	return false
+}
+
+// introduce starts the sequence or string introduced by the C1 control c.
+// The raw bytes of 7-bit forms must already be recorded.
+func (t *Tokenizer) introduce(c int, b byte) {
+	if len(t.raw) == 0 {
+		t.raw = appendRaw(t.raw, c, b)
+	}
+	switch c {
+	case 0x9b:
+		t.state = stateCSIEntry
+	case 0x90:
+		t.state, t.kind = stateDCSEntry, DCS
+	case 0x9d:
+		t.state, t.kind = stateOSCString, OSC
+	case 0x98:
+		t.state, t.kind = stateSOSPMAPCString, SOS
+	case 0x9e:
+		t.state, t.kind = stateSOSPMAPCString, PM
+	case 0x9f:
+		t.state, t.kind = stateSOSPMAPCString, APC
+	}
+}
+
+// enter starts a new sequence in the state s with the character c.
+func (t *Tokenizer) enter(s state, c int, b byte) {
+	t.reset()
+	t.raw = appendRaw(t.raw, c, b)
+	t.state = s
+}
+
+// inString returns true if the tokenizer is reading a control string.
+func (t *Tokenizer) inString() bool {
+	switch t.state {
+	case stateDCSPassthrough, stateDCSIgnore, stateOSCString, stateSOSPMAPCString:
+		return true
+	default:
+		return false
+	}
+}
+
+// isC0 returns true if c is a C0 control that's executed in the middle of sequences.
+func isC0(c int) bool {
+	return c <= 0x17 || c == 0x19 || (c >= 0x1c && c <= 0x1f)
+}
+
+// emitText returns the text read so far. Unless all is true, a trailing incomplete UTF-8 character is held.
+func (t *Tokenizer) emitText(all bool) {
+	n := len(t.text)
+	if !all {
+		n = completeRunes(t.text)
+	}
+	if n == 0 {
+		return
+	}
+	t.tokens = append(t.tokens, Token{
+		Kind: Text,
+		Raw:  append([]byte(nil), t.text[:n]...),
+	})
+	t.text = append(t.text[:0], t.text[n:]...)
+}
+
+// completeRunes returns the length of b without its trailing incomplete UTF-8 character.
+func completeRunes(b []byte) int {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if utf8.FullRune(b[i:]) {
+			return len(b)
+		}
+		return i
+	}
+	return len(b)
+}
+
+func (t *Tokenizer) emitControl(c int, b byte) {
+	t.tokens = append(t.tokens, Token{
+		Kind:    Control,
+		Raw:     appendRaw(nil, c, b),
+		Control: rune(c),
+	})
+}
+
+// emitSequence returns the escape or control sequence read so far and goes back to the ground state.
+func (t *Tokenizer) emitSequence(kind Kind) {
+	tok := Token{
+		Kind: kind,
+		Raw:  append([]byte(nil), t.raw...),
+	}
+	if kind != Invalid {
+		tok.Prefix = t.prefix
+		tok.Params = parseParams(t.params)
+		tok.Intermediates = copyBytes(t.intermediates)
+		tok.Final = t.final
+	}
+	t.tokens = append(t.tokens, tok)
+	t.reset()
+}
+
+// emitString returns the control string read so far and goes back to the ground state.
+func (t *Tokenizer) emitString() {
+	tok := Token{
+		Kind: t.kind,
+		Raw:  append([]byte(nil), t.raw...),
+		Data: copyBytes(t.data),
+	}
+	switch t.state {
+	case stateDCSPassthrough:
+		tok.Prefix = t.prefix
+		tok.Params = parseParams(t.params)
+		tok.Intermediates = copyBytes(t.intermediates)
+		tok.Final = t.final
+	case stateDCSIgnore:
+		tok = Token{Kind: Invalid, Raw: tok.Raw}
+	}
+	t.tokens = append(t.tokens, tok)
+	t.reset()
+}
+
+// abort returns the incomplete sequence read so far as an Invalid token.
+func (t *Tokenizer) abort() {
+	if t.state == stateGround {
+		return
+	}
+	if len(t.raw) > 0 {
+		t.tokens = append(t.tokens, Token{
+			Kind: Invalid,
+			Raw:  append([]byte(nil), t.raw...),
+		})
+	}
+	t.reset()
+}
+
+// reset goes back to the ground state.
+func (t *Tokenizer) reset() {
+	t.state = stateGround
+	t.kind = 0
+	t.raw = t.raw[:0]
+	t.stringEscape = false
+	t.prefix = 0
+	t.params = t.params[:0]
+	t.nparams = 0
+	t.intermediates = t.intermediates[:0]
+	t.final = 0
+	t.data = t.data[:0]
+}
+
+// parseParams parses parameters such as "1;38:2::25:255:203".
+func parseParams(b []byte) []Param {
+	if len(b) == 0 {
+		return nil
+	}
+	var (
+		params []Param
+		param  = Param{Default}
+	)
+	for _, c := range b {
+		switch c {
+		case ';':
+			params = append(params, param)
+			param = Param{Default}
+		case ':':
+			param = append(param, Default)
+		default:
+			v := &param[len(param)-1]
+			if *v == Default {
+				*v = 0
+			}
+			if *v < maxParamValue {
+				*v = *v*10 + int(c-'0')
+			}
+			if *v > maxParamValue {
+				*v = maxParamValue
+			}
+		}
+	}
+	return append(params, param)
+}
+
+func copyBytes(b []byte) []byte {
+	if len(b) == 0 {
+		return nil
+	}
+	return append([]byte(nil), b...)
+}
@@ -0,0 +1,276 @@
+package ansi
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := map[string]struct {
+		input string
+
+		wanted []Token
+	}{
+		"text and controls": {
+			input: "hello\r\nworld\a",
+			wanted: []Token{
+				{Kind: Text, Raw: []byte("hello")},
+				{Kind: Control, Raw: []byte("\r"), Control: '\r'},
+				{Kind: Control, Raw: []byte("\n"), Control: '\n'},
+				{Kind: Text, Raw: []byte("world")},
+				{Kind: Control, Raw: []byte("\a"), Control: '\a'},
+			},
+		},
+		"utf-8 text": {
+			input: "£ 日本",
+			wanted: []Token{
+				{Kind: Text, Raw: []byte("£ 日本")},
+			},
+		},
+		"utf-8 encoded c1 controls": {
+			input: "a\u0085\u009b1m",
+			wanted: []Token{
+				{Kind: Text, Raw: []byte("a")},
+				{Kind: Control, Raw: []byte("\u0085"), Control: 0x85},
+				{Kind: CSI, Raw: []byte("\u009b1m"), Params: []Param{{1}}, Final: 'm'},
+			},
+		},
+		"control character inside a control sequence": {
+			input: "\x1b[3\n1m",
+			wanted: []Token{
+				{Kind: Control, Raw: []byte("\n"), Control: '\n'},
+				{Kind: CSI, Raw: []byte("\x1b[31m"), Params: []Param{{31}}, Final: 'm'},
+			},
+		},
+		"escape sequences": {
+			input: "\x1b7\x1b(B",
+			wanted: []Token{
+				{Kind: Escape, Raw: []byte("\x1b7"), Final: '7'},
+				{Kind: Escape, Raw: []byte("\x1b(B"), Intermediates: []byte("("), Final: 'B'},
+			},
+		},
+		"control sequence with parameters": {
+			input: "\x1b[1;;31m",
+			wanted: []Token{
+				{Kind: CSI, Raw: []byte("\x1b[1;;31m"), Params: []Param{{1}, {Default}, {31}}, Final: 'm'},
+			},
+		},
+		"control sequence with subparameters": {
+			input: "\x1b[38:2::25:255:203;4:3m",
+			wanted: []Token{
+				{
+					Kind:   CSI,
+					Raw:    []byte("\x1b[38:2::25:255:203;4:3m"),
+					Params: []Param{{38, 2, Default, 25, 255, 203}, {4, 3}},
+					Final:  'm',
+				},
+			},
+		},
+		"control sequence with a prefix and intermediates": {
+			input: "\x1b[?25l\x1b[2 q",
+			wanted: []Token{
+				{Kind: CSI, Raw: []byte("\x1b[?25l"), Prefix: '?', Params: []Param{{25}}, Final: 'l'},
+				{Kind: CSI, Raw: []byte("\x1b[2 q"), Params: []Param{{2}}, Intermediates: []byte(" "), Final: 'q'},
+			},
+		},
+		"control sequence with a misplaced prefix": {
+			input: "\x1b[1?2hok",
+			wanted: []Token{
+				{Kind: Invalid, Raw: []byte("\x1b[1?2h")},
+				{Kind: Text, Raw: []byte("ok")},
+			},
+		},
+		"control characters inside a control sequence": {
+			input: "\x1b[1\n2m",
+			wanted: []Token{
+				{Kind: Control, Raw: []byte("\n"), Control: '\n'},
+				{Kind: CSI, Raw: []byte("\x1b[12m"), Params: []Param{{12}}, Final: 'm'},
+			},
+		},
+		"cancelled control sequence": {
+			input: "\x1b[31\x18ok",
+			wanted: []Token{
+				{Kind: Invalid, Raw: []byte("\x1b[31")},
+				{Kind: Control, Raw: []byte("\x18"), Control: 0x18},
+				{Kind: Text, Raw: []byte("ok")},
+			},
+		},
+		"large parameters are capped": {
+			input: "\x1b[99999999999999999999999C",
+			wanted: []Token{
+				{Kind: CSI, Raw: []byte("\x1b[99999999999999999999999C"), Params: []Param{{maxParamValue}}, Final: 'C'},
+			},
+		},
+		"operating system commands": {
+			input: "\x1b]0;title\a\x1b]8;;https://example.com\x1b\\",
+			wanted: []Token{
+				{Kind: OSC, Raw: []byte("\x1b]0;title\a"), Data: []byte("0;title")},
+				{Kind: OSC, Raw: []byte("\x1b]8;;https://example.com\x1b\\"), Data: []byte("8;;https://example.com")},
+			},
+		},
+		"operating system command interrupted by a sequence": {
+			input: "\x1b]0;title\x1b[m",
+			wanted: []Token{
+				{Kind: OSC, Raw: []byte("\x1b]0;title"), Data: []byte("0;title")},
+				{Kind: CSI, Raw: []byte("\x1b[m"), Final: 'm'},
+			},
+		},
+		"device control string": {
+			input: "\x1bP1$r0;48:2::1:2:3m\x1b\\",
+			wanted: []Token{
+				{
+					Kind:          DCS,
+					Raw:           []byte("\x1bP1$r0;48:2::1:2:3m\x1b\\"),
+					Params:        []Param{{1}},
+					Intermediates: []byte("$"),
+					Final:         'r',
+					Data:          []byte("0;48:2::1:2:3m"),
+				},
+			},
+		},
+		"device control string terminated by a utf-8 encoded st": {
+			input: "\u0090q#0\u009c",
+			wanted: []Token{
+				{Kind: DCS, Raw: []byte("\u0090q#0\u009c"), Final: 'q', Data: []byte("#0")},
+			},
+		},
+		"sos, pm and apc strings": {
+			input: "\x1bXa\x1b\\\x1b^b\x1b\\\x1b_Gf=100;data\x1b\\",
+			wanted: []Token{
+				{Kind: SOS, Raw: []byte("\x1bXa\x1b\\"), Data: []byte("a")},
+				{Kind: PM, Raw: []byte("\x1b^b\x1b\\"), Data: []byte("b")},
+				{Kind: APC, Raw: []byte("\x1b_Gf=100;data\x1b\\"), Data: []byte("Gf=100;data")},
+			},
+		},
+		"incomplete sequence": {
+			input: "ok\x1b[38;2",
+			wanted: []Token{
+				{Kind: Text, Raw: []byte("ok")},
+				{Kind: Invalid, Raw: []byte("\x1b[38;2")},
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			got := Tokenize([]byte(tc.input))
+
+			// Then
+			if !reflect.DeepEqual(got, tc.wanted) {
+				t.Errorf("expected %#v, got %#v", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestTokenizer_Feed(t *testing.T) {
+	testCases := map[string]struct {
+		chunks []string
+
+		wanted []Kind
+	}{
+		"sequence split across chunks": {
+			chunks: []string{"a\x1b", "[38;2;255;1", "28;0", "mb"},
+			wanted: []Kind{Text, CSI, Text},
+		},
+		"utf-8 character split across chunks": {
+			chunks: []string{"\xe6\x97", "\xa5"},
+			wanted: []Kind{Text},
+		},
+		"utf-8 encoded c1 control split across chunks": {
+			chunks: []string{"\xc2", "\x9b", "m"},
+			wanted: []Kind{CSI},
+		},
+		"string terminator split across chunks": {
+			chunks: []string{"\x1b]0;title\x1b", "\\"},
+			wanted: []Kind{OSC},
+		},
+		"unterminated string longer than the limit": {
+			chunks: []string{"x\x1b]0;title", strings.Repeat("a", maxSequenceLen), "b"},
+			wanted: []Kind{Text, Invalid, Text, Text},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var tokenizer Tokenizer
+			var got []Token
+
+			// When
+			for _, chunk := range tc.chunks {
+				got = append(got, tokenizer.Feed([]byte(chunk))...)
+			}
+
+			// Then
+			if rest := tokenizer.Flush(); len(rest) != 0 {
+				t.Errorf("expected no held tokens, got %v", rest)
+			}
+			var kinds []Kind
+			var raw []byte
+			for _, tok := range got {
+				kinds = append(kinds, tok.Kind)
+				raw = append(raw, tok.Raw...)
+			}
+			if !reflect.DeepEqual(kinds, tc.wanted) {
+				t.Errorf("expected kinds %v, got %v", tc.wanted, kinds)
+			}
+			if wanted := []byte(joinChunks(tc.chunks)); !bytes.Equal(raw, wanted) {
+				t.Errorf("expected raw bytes %q, got %q", wanted, raw)
+			}
+		})
+	}
+}
+
+func TestParam_Value(t *testing.T) {
+	testCases := map[string]struct {
+		param Param
+
+		wanted int
+	}{
+		"omitted": {
+			param:  Param{Default},
+			wanted: 1,
+		},
+		"empty": {
+			param:  nil,
+			wanted: 1,
+		},
+		"set": {
+			param:  Param{5, 2},
+			wanted: 5,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			got := tc.param.Value(1)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %d, got %d", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func joinChunks(chunks []string) string {
+	var s string
+	for _, c := range chunks {
+		s += c
+	}
+	return s
+}
@@ -0,0 +1,153 @@
+package termcolor
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/efekarakus/termcolor/ansi"
+)
+
+// ErrNoBackground is returned when the background color of the terminal can't be determined.
+var ErrNoBackground = errors.New("termcolor: background color is unknown")
+
+// requestBackground asks the terminal for its background color with OSC 11.
+const requestBackground = "\x1b]11;?\x1b\\"
+
+// QueryBackground asks the terminal for its background color.
+// The file descriptor must be a terminal that can be read from and written to, such as os.Stdin or "/dev/tty".
+//
+// The terminal is temporarily put in raw mode and sent an OSC 11 query followed by a primary device attributes
+// request. If the terminal answers the latter but not the former, then ErrProbeUnsupported is returned.
+// If the terminal doesn't respond at all before the timeout, then ErrProbeTimeout is returned.
+func QueryBackground(f FileDescriptor, opts ...ProbeOption) (RGB, error) {
+	c := newProbeConfig(opts)
+	resp, err := queryTerminal(int(f.Fd()), []byte(c.multiplexer.Wrap(requestBackground+requestDA1)), c.timeout, hasDA1Response)
+	if err != nil && err != ErrProbeTimeout {
+		return RGB{}, err
+	}
+	spec, ok := oscResponse(resp, "11;")
+	if !ok {
+		if err != nil {
+			return RGB{}, err
+		}
+		return RGB{}, ErrProbeUnsupported
+	}
+	bg, ok := parseColorSpec(spec)
+	if !ok {
+		return RGB{}, ErrProbeUnsupported
+	}
+	return bg, nil
+}
+
+// Background returns the background color of the terminal.
+// See Detector.Background.
+func Background(f FileDescriptor, opts ...ProbeOption) (RGB, error) {
+	return NewDetector().Background(f, opts...)
+}
+
+// Background returns the background color of the terminal.
+// If the file descriptor is a terminal, the terminal is queried with QueryBackground, through the multiplexer
+// if there is one. Otherwise, or if the
+// terminal doesn't answer, the color is taken from the COLORFGBG environment variable set by some terminals.
+// If neither is available, then returns ErrNoBackground.
+func (d *Detector) Background(f FileDescriptor, opts ...ProbeOption) (RGB, error) {
+	if d.isTerminal(f.Fd()) {
+		opts = append([]ProbeOption{WithPassthrough(d.Multiplexer())}, opts...)
+		if bg, err := QueryBackground(f, opts...); err == nil {
+			return bg, nil
+		}
+	}
+	if v, ok := d.lookupEnv("COLORFGBG"); ok {
+		if bg, ok := parseColorFgBg(v); ok {
+			return bg, nil
+		}
+	}
+	return RGB{}, ErrNoBackground
+}
+
+// oscResponse returns the data of the first operating system command in resp that starts with prefix,
+// with the prefix removed. The command can be terminated by ST or BEL.
+func oscResponse(resp []byte, prefix string) (string, bool) {
+	for _, tok := range ansi.Tokenize(resp) {
+		if tok.Kind != ansi.OSC || !strings.HasPrefix(string(tok.Data), prefix) {
+			continue
+		}
+		if !endsWithST(tok.Raw) && !bytes.HasSuffix(tok.Raw, []byte("\a")) {
+			// The command was interrupted.
+			continue
+		}
+		return strings.TrimPrefix(string(tok.Data), prefix), true
+	}
+	return "", false
+}
+
+// parseColorSpec parses a color in the XParseColor formats returned by terminals:
+// "rgb:r/g/b" and "rgba:r/g/b/a" with 1 to 4 hexadecimal digits per component, or "#rgb" with the same
+// number of digits for each component.
+func parseColorSpec(spec string) (RGB, bool) {
+	var parts []string
+	switch {
+	case strings.HasPrefix(spec, "rgb:"):
+		parts = strings.Split(strings.TrimPrefix(spec, "rgb:"), "/")
+		if len(parts) != 3 {
+			return RGB{}, false
+		}
+	case strings.HasPrefix(spec, "rgba:"):
+		parts = strings.Split(strings.TrimPrefix(spec, "rgba:"), "/")
+		if len(parts) != 4 {
+			return RGB{}, false
+		}
+	case strings.HasPrefix(spec, "#"):
+		hex := strings.TrimPrefix(spec, "#")
+		n := len(hex) / 3
+		if n == 0 || len(hex)%3 != 0 {
+			return RGB{}, false
+		}
+		parts = []string{hex[:n], hex[n : 2*n], hex[2*n:]}
+	default:
+		return RGB{}, false
+	}
+	var components [3]uint8
+	for i := range components {
+		v, ok := scaleHex(parts[i])
+		if !ok {
+			return RGB{}, false
+		}
+		components[i] = v
+	}
+	return RGB{components[0], components[1], components[2]}, true
+}
+
+// scaleHex converts a hexadecimal component of 1 to 4 digits to an 8-bit value.
+func scaleHex(s string) (uint8, bool) {
+	if len(s) == 0 || len(s) > 4 {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(s, 16, 16)
+	if err != nil {
+		return 0, false
+	}
+	max := uint64(1)<<(4*uint(len(s))) - 1
+	return uint8((v*255 + max/2) / max), true
+}
+
+// parseColorFgBg returns the background color from the COLORFGBG environment variable.
+// The variable has the form "fg;bg" or "fg;default;bg" where colors are indexes of the basic palette.
+// A trailing "default", such as in rxvt's "0;default", is skipped in favor of the previous field.
+func parseColorFgBg(v string) (RGB, bool) {
+	fields := strings.Split(v, ";")
+	if len(fields) < 2 {
+		return RGB{}, false
+	}
+	last := len(fields) - 1
+	for last > 0 && fields[last] == "default" {
+		last--
+	}
+	i, err := strconv.Atoi(fields[last])
+	if err != nil || i < 0 || i >= len(basicPalette) {
+		return RGB{}, false
+	}
+	return basicPalette[i], true
+}
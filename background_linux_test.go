@@ -0,0 +1,92 @@
+package termcolor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQueryBackground(t *testing.T) {
+	testCases := map[string]struct {
+		answers map[string]string
+
+		wanted     RGB
+		wantedDark bool
+		wantedErr  error
+	}{
+		"dark terminal terminated by ST": {
+			answers: map[string]string{
+				requestBackground: "\x1b]11;rgb:1e1e/1e1e/2e2e\x1b\\",
+				requestDA1:        "\x1b[?62;22c",
+			},
+			wanted:     RGB{30, 30, 46},
+			wantedDark: true,
+		},
+		"light terminal terminated by BEL": {
+			answers: map[string]string{
+				requestBackground: "\x1b]11;rgb:fdfd/f6f6/e3e3\a",
+				requestDA1:        "\x1b[?62;22c",
+			},
+			wanted: RGB{253, 246, 227},
+		},
+		"terminal without OSC 11": {
+			answers: map[string]string{
+				requestDA1: "\x1b[?1;2c",
+			},
+			wantedErr: ErrProbeUnsupported,
+		},
+		"terminal that doesn't respond": {
+			wantedErr: ErrProbeTimeout,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			master, slave := openTestPTY(t)
+			defer slave.Close()
+			defer master.Close()
+			fakeTerminal(master, tc.answers)
+
+			// When
+			bg, err := QueryBackground(slave, WithProbeTimeout(100*time.Millisecond))
+
+			// Then
+			if err != tc.wantedErr {
+				t.Fatalf("expected error %v, got %v", tc.wantedErr, err)
+			}
+			if bg != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, bg)
+			}
+			if bg.IsDark() != tc.wantedDark && err == nil {
+				t.Errorf("expected dark %v, got %v", tc.wantedDark, bg.IsDark())
+			}
+		})
+	}
+}
+
+func TestDetector_Background_FallsBackToColorFgBg(t *testing.T) {
+	// Given
+	master, slave := openTestPTY(t)
+	defer slave.Close()
+	defer master.Close()
+	fakeTerminal(master, map[string]string{
+		requestDA1: "\x1b[?1;2c",
+	})
+	d := NewDetector(WithEnv(map[string]string{
+		"COLORFGBG": "0;15",
+	}))
+
+	// When
+	bg, err := d.Background(slave, WithProbeTimeout(100*time.Millisecond))
+
+	// Then
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if wanted := (RGB{255, 255, 255}); bg != wanted {
+		t.Errorf("expected %v, got %v", wanted, bg)
+	}
+}
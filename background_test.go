@@ -0,0 +1,146 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestParseColorSpec(t *testing.T) {
+	testCases := map[string]struct {
+		spec string
+
+		wanted   RGB
+		wantedOK bool
+	}{
+		"16-bit components": {
+			spec:     "rgb:1e1e/1e1e/2e2e",
+			wanted:   RGB{30, 30, 46},
+			wantedOK: true,
+		},
+		"8-bit components": {
+			spec:     "rgb:ff/80/00",
+			wanted:   RGB{255, 128, 0},
+			wantedOK: true,
+		},
+		"4-bit components": {
+			spec:     "rgb:f/8/0",
+			wanted:   RGB{255, 136, 0},
+			wantedOK: true,
+		},
+		"with alpha": {
+			spec:     "rgba:ffff/ffff/ffff/ffff",
+			wanted:   RGB{255, 255, 255},
+			wantedOK: true,
+		},
+		"hash form": {
+			spec:     "#fdf6e3",
+			wanted:   RGB{253, 246, 227},
+			wantedOK: true,
+		},
+		"missing component": {
+			spec: "rgb:ffff/ffff",
+		},
+		"invalid hex": {
+			spec: "rgb:gg/00/00",
+		},
+		"too many digits": {
+			spec: "rgb:fffff/0/0",
+		},
+		"unknown format": {
+			spec: "white",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			c, ok := parseColorSpec(tc.spec)
+
+			// Then
+			if ok != tc.wantedOK {
+				t.Fatalf("expected ok %v, got %v", tc.wantedOK, ok)
+			}
+			if c != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, c)
+			}
+		})
+	}
+}
+
+func TestDetector_Background(t *testing.T) {
+	testCases := map[string]struct {
+		envs map[string]string
+
+		wanted    RGB
+		wantedErr error
+	}{
+		"dark background from COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "15;0",
+			},
+			wanted: RGB{0, 0, 0},
+		},
+		"light background from rxvt's COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "0;default;15",
+			},
+			wanted: RGB{255, 255, 255},
+		},
+		"default background in rxvt's COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "0;default",
+			},
+			wanted: RGB{0, 0, 0},
+		},
+		"default background after a light color in COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "15;default",
+			},
+			wanted: RGB{255, 255, 255},
+		},
+		"unknown background in COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "default;default",
+			},
+			wantedErr: ErrNoBackground,
+		},
+		"out of bounds background in COLORFGBG": {
+			envs: map[string]string{
+				"COLORFGBG": "0;16",
+			},
+			wantedErr: ErrNoBackground,
+		},
+		"without COLORFGBG": {
+			wantedErr: ErrNoBackground,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return false
+				}),
+			)
+
+			// When
+			bg, err := d.Background(os.Stdout)
+
+			// Then
+			if err != tc.wantedErr {
+				t.Fatalf("expected error %v, got %v", tc.wantedErr, err)
+			}
+			if bg != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, bg)
+			}
+		})
+	}
+}
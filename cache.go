@@ -0,0 +1,72 @@
+package termcolor
+
+import (
+	"sync"
+)
+
+// CachedDetector is a Detector that computes the color level of each file descriptor once.
+// It's safe for concurrent use, and its cached path doesn't allocate, so it can be called from hot paths such as
+// logging.
+type CachedDetector struct {
+	d *Detector
+
+	mu     sync.RWMutex
+	levels map[uintptr]Level
+}
+
+// NewCachedDetector returns a CachedDetector that detects levels with a Detector configured with opts.
+func NewCachedDetector(opts ...DetectorOption) *CachedDetector {
+	return &CachedDetector{
+		d:      NewDetector(opts...),
+		levels: make(map[uintptr]Level),
+	}
+}
+
+// Level returns the color level that's supported by the file descriptor.
+// The level is detected on the first call for the file descriptor and then reused until Reset is called.
+func (c *CachedDetector) Level(f FileDescriptor) Level {
+	fd := f.Fd()
+	c.mu.RLock()
+	l, ok := c.levels[fd]
+	c.mu.RUnlock()
+	if ok {
+		return l
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if l, ok := c.levels[fd]; ok {
+		// Another goroutine detected the level in the meantime.
+		return l
+	}
+	l = c.d.Level(f)
+	c.levels[fd] = l
+	return l
+}
+
+// Reset forgets the cached levels and the output of the commands run by the detector, such as the options of tmux,
+// so that the next calls to Level detect them again.
+// It's meant for tests, and for programs that change their environment variables at runtime.
+func (c *CachedDetector) Reset() {
+	c.d.commands.reset()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for fd := range c.levels {
+		delete(c.levels, fd)
+	}
+}
+
+// defaultCache caches the levels of the current process.
+var defaultCache = NewCachedDetector()
+
+// CachedLevel returns the color level that's supported by the file descriptor, like SupportLevel, but only
+// detects it once per file descriptor. See ResetCache.
+func CachedLevel(f FileDescriptor) Level {
+	return defaultCache.Level(f)
+}
+
+// ResetCache forgets the levels cached by CachedLevel, and the options of tmux remembered by the detectors that use
+// the default CommandRunner.
+func ResetCache() {
+	defaultCache.Reset()
+}
@@ -0,0 +1,112 @@
+package termcolor
+
+import (
+	"os"
+	"sync"
+	"testing"
+)
+
+func TestCachedDetector_Level(t *testing.T) {
+	// Given
+	env := map[string]string{"TERM": "xterm-256color"}
+	var mu sync.Mutex
+	lookups := 0
+	c := NewCachedDetector(
+		WithArgs(nil),
+		WithLookupEnv(func(key string) (string, bool) {
+			mu.Lock()
+			defer mu.Unlock()
+			lookups++
+			v, ok := env[key]
+			return v, ok
+		}),
+		WithTerminalChecker(func(fd uintptr) bool {
+			return fd == 1
+		}),
+		WithTerminfoDirs(),
+		WithCommandRunner(nil),
+	)
+
+	// When
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if l := c.Level(os.Stdout); l != Level256 {
+				t.Errorf("expected %v, got %v", Level256, l)
+			}
+		}()
+	}
+	wg.Wait()
+	first := lookups
+	c.Level(os.Stdout)
+
+	// Then
+	if lookups != first {
+		t.Errorf("expected the cached level to be reused, got %d lookups after %d", lookups, first)
+	}
+	if l := c.Level(os.Stderr); l != LevelNone {
+		t.Errorf("expected stderr to be cached separately, got %v", l)
+	}
+
+	// When
+	mu.Lock()
+	env["TERM"] = "dumb"
+	mu.Unlock()
+	c.Reset()
+
+	// Then
+	if l := c.Level(os.Stdout); l != LevelNone {
+		t.Errorf("expected the level to be detected again after Reset, got %v", l)
+	}
+}
+
+func TestCachedDetector_LevelDoesNotAllocate(t *testing.T) {
+	// Given
+	c := NewCachedDetector(WithArgs(nil), WithEnv(map[string]string{"FORCE_COLOR": "3"}))
+	c.Level(os.Stdout)
+
+	// When
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Level(os.Stdout)
+	})
+
+	// Then
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+}
+
+func BenchmarkDetector_Level(b *testing.B) {
+	d := NewDetector(WithArgs([]string{"cli"}), WithEnv(map[string]string{"TERM": "xterm-256color"}),
+		WithTerminalChecker(func(fd uintptr) bool { return true }), WithTerminfoDirs(), WithCommandRunner(nil))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		d.Level(os.Stdout)
+	}
+}
+
+func BenchmarkCachedDetector_Level(b *testing.B) {
+	c := NewCachedDetector(WithArgs([]string{"cli"}), WithEnv(map[string]string{"TERM": "xterm-256color"}),
+		WithTerminalChecker(func(fd uintptr) bool { return true }), WithTerminfoDirs(), WithCommandRunner(nil))
+	c.Level(os.Stdout)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Level(os.Stdout)
+	}
+}
+
+func BenchmarkCachedDetector_LevelParallel(b *testing.B) {
+	c := NewCachedDetector(WithArgs([]string{"cli"}), WithEnv(map[string]string{"TERM": "xterm-256color"}),
+		WithTerminalChecker(func(fd uintptr) bool { return true }), WithTerminfoDirs(), WithCommandRunner(nil))
+	c.Level(os.Stdout)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c.Level(os.Stdout)
+		}
+	})
+}
@@ -0,0 +1,196 @@
+package termcolor
+
+import "strings"
+
+// Support is whether a terminal supports a feature.
+type Support int
+
+// Support values of a feature.
+const (
+	// SupportUnknown represents a feature that couldn't be determined from the environment.
+	SupportUnknown Support = iota
+	// Supported represents a feature that the terminal supports.
+	Supported
+	// Unsupported represents a feature that the terminal doesn't support.
+	Unsupported
+)
+
+// String returns the name of the support value.
+func (s Support) String() string {
+	switch s {
+	case Supported:
+		return "supported"
+	case Unsupported:
+		return "unsupported"
+	default:
+		return "unknown"
+	}
+}
+
+// Feature is a set of terminal features other than colors.
+type Feature int
+
+// Features that can be detected.
+const (
+	// FeatureItalic represents italic text, SGR 3.
+	FeatureItalic Feature = 1 << iota
+	// FeatureStrikethrough represents crossed-out text, SGR 9.
+	FeatureStrikethrough
+	// FeatureCurlyUnderline represents curly underlines, SGR 4:3.
+	FeatureCurlyUnderline
+	// FeatureColoredUnderline represents underline colors, SGR 58.
+	FeatureColoredUnderline
+	// FeatureHyperlinks represents OSC 8 hyperlinks.
+	FeatureHyperlinks
+	// FeatureSynchronizedOutput represents synchronized output, mode 2026.
+	FeatureSynchronizedOutput
+	// FeatureBracketedPaste represents bracketed paste, mode 2004.
+	FeatureBracketedPaste
+	// FeatureBoxDrawing represents the Unicode box drawing characters.
+	FeatureBoxDrawing
+)
+
+// features are the single features in the order of Profile's fields.
+var features = []Feature{
+	FeatureItalic,
+	FeatureStrikethrough,
+	FeatureCurlyUnderline,
+	FeatureColoredUnderline,
+	FeatureHyperlinks,
+	FeatureSynchronizedOutput,
+	FeatureBracketedPaste,
+	FeatureBoxDrawing,
+}
+
+// Profile is the color level of a terminal along with the support of its other features.
+type Profile struct {
+	Level              Level
+	Italic             Support
+	Strikethrough      Support
+	CurlyUnderline     Support
+	ColoredUnderline   Support
+	Hyperlinks         Support
+	SynchronizedOutput Support
+	BracketedPaste     Support
+	BoxDrawing         Support
+}
+
+// Support returns the support of a single feature.
+func (p Profile) Support(f Feature) Support {
+	if s := p.field(f); s != nil {
+		return *s
+	}
+	return SupportUnknown
+}
+
+func (p *Profile) field(f Feature) *Support {
+	switch f {
+	case FeatureItalic:
+		return &p.Italic
+	case FeatureStrikethrough:
+		return &p.Strikethrough
+	case FeatureCurlyUnderline:
+		return &p.CurlyUnderline
+	case FeatureColoredUnderline:
+		return &p.ColoredUnderline
+	case FeatureHyperlinks:
+		return &p.Hyperlinks
+	case FeatureSynchronizedOutput:
+		return &p.SynchronizedOutput
+	case FeatureBracketedPaste:
+		return &p.BracketedPaste
+	case FeatureBoxDrawing:
+		return &p.BoxDrawing
+	default:
+		return nil
+	}
+}
+
+// Capabilities returns the color level and the features that are supported by the file descriptor.
+func Capabilities(f FileDescriptor) Profile {
+	return NewDetector().Capabilities(f)
+}
+
+// Capabilities returns the color level and the features that are supported by the file descriptor.
+// Features are looked up in order from:
+//  1. The terminal state: none are supported by file descriptors that aren't terminals or by dumb terminals.
+//  2. The locale for box drawing, which requires UTF-8.
+//  3. The registered terminals, which list all the features they support. They're skipped inside multiplexers.
+//  4. The terminfo entry of TERM, such as the sitm capability for italics.
+//
+// Features that none of them determine are unknown. Hyperlinks are reported as by SupportsHyperlinks.
+func (d *Detector) Capabilities(f FileDescriptor) Profile {
+	e := detection{d: d}
+	p := Profile{Level: e.detect(f).standard()}
+	e.capabilities(f, &p)
+	return p
+}
+
+func (e *detection) capabilities(f FileDescriptor, p *Profile) {
+	e.lookupFeatures(f, p)
+	// Hyperlinks follow their own rules, such as FORCE_HYPERLINK.
+	p.Hyperlinks = e.hyperlinks(f)
+}
+
+func (e *detection) lookupFeatures(f FileDescriptor, p *Profile) {
+	if !e.isTerminal(f.Fd()) || e.isDumbTerminal() {
+		for _, ft := range features {
+			*p.field(ft) = Unsupported
+		}
+		return
+	}
+	p.BoxDrawing = e.lookupLocale()
+	if e.multiplexer() == NoMultiplexer {
+		lookupEnv := e.onceLookupEnv()
+		if t, ok := findTerminal(lookupEnv); ok {
+			supported := t.features(lookupEnv)
+			for _, ft := range features {
+				if s := p.field(ft); *s == SupportUnknown {
+					*s = Unsupported
+					if supported&ft != 0 {
+						*s = Supported
+					}
+				}
+			}
+			return
+		}
+	}
+	ti, ok := e.terminfo()
+	if !ok {
+		return
+	}
+	for _, ft := range features {
+		if s := p.field(ft); *s == SupportUnknown {
+			*s = ti.support(ft)
+		}
+	}
+}
+
+// lookupLocale returns whether the character set of the locale can encode box drawing characters.
+// The first variable set among LC_ALL, LC_CTYPE and LANG names the locale, such as "en_US.UTF-8".
+func (e *detection) lookupLocale() Support {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		locale := e.getenv(key)
+		if locale == "" {
+			continue
+		}
+		if isUTF8Locale(locale) {
+			return Supported
+		}
+		return Unsupported
+	}
+	return SupportUnknown
+}
+
+func isUTF8Locale(locale string) bool {
+	i := strings.Index(locale, ".")
+	if i == -1 {
+		return false
+	}
+	charset := locale[i+1:]
+	if j := strings.Index(charset, "@"); j != -1 {
+		// Strip the modifier, such as "@euro".
+		charset = charset[:j]
+	}
+	return strings.EqualFold(strings.Replace(charset, "-", "", -1), "utf8")
+}
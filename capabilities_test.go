@@ -0,0 +1,165 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetector_Capabilities(t *testing.T) {
+	testCases := map[string]struct {
+		envs       map[string]string
+		isTerminal bool
+
+		wanted Profile
+	}{
+		"not a terminal": {
+			envs: map[string]string{
+				"KITTY_WINDOW_ID": "1",
+			},
+			wanted: Profile{
+				Italic:             Unsupported,
+				Strikethrough:      Unsupported,
+				CurlyUnderline:     Unsupported,
+				ColoredUnderline:   Unsupported,
+				Hyperlinks:         Unsupported,
+				SynchronizedOutput: Unsupported,
+				BracketedPaste:     Unsupported,
+				BoxDrawing:         Unsupported,
+			},
+		},
+		"kitty": {
+			envs: map[string]string{
+				"KITTY_WINDOW_ID": "1",
+				"LANG":            "en_US.UTF-8",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level:              Level16M,
+				Italic:             Supported,
+				Strikethrough:      Supported,
+				CurlyUnderline:     Supported,
+				ColoredUnderline:   Supported,
+				Hyperlinks:         Supported,
+				SynchronizedOutput: Supported,
+				BracketedPaste:     Supported,
+				BoxDrawing:         Supported,
+			},
+		},
+		"Terminal.app with the C locale": {
+			envs: map[string]string{
+				"TERM_PROGRAM": "Apple_Terminal",
+				"LC_ALL":       "C",
+				"LANG":         "en_US.UTF-8",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level:              Level256,
+				Italic:             Supported,
+				Strikethrough:      Unsupported,
+				CurlyUnderline:     Unsupported,
+				ColoredUnderline:   Unsupported,
+				Hyperlinks:         Unsupported,
+				SynchronizedOutput: Unsupported,
+				BracketedPaste:     Supported,
+				BoxDrawing:         Unsupported,
+			},
+		},
+		"VTE 0.50": {
+			envs: map[string]string{
+				"VTE_VERSION": "5002",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level:              Level16M,
+				Italic:             Supported,
+				Strikethrough:      Supported,
+				CurlyUnderline:     Unsupported,
+				ColoredUnderline:   Unsupported,
+				Hyperlinks:         Supported,
+				SynchronizedOutput: Unsupported,
+				BracketedPaste:     Supported,
+				BoxDrawing:         Supported,
+			},
+		},
+		"terminfo entry with extended styles": {
+			envs: map[string]string{
+				"TERM":     "termcolor-styles",
+				"TERMINFO": "testdata/terminfo",
+				"LC_CTYPE": "de_DE.utf8@euro",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level:              Level256,
+				Italic:             Supported,
+				Strikethrough:      Supported,
+				CurlyUnderline:     Supported,
+				ColoredUnderline:   Supported,
+				SynchronizedOutput: Supported,
+				BracketedPaste:     Supported,
+				BoxDrawing:         Supported,
+			},
+		},
+		"known terminal inside tmux": {
+			envs: map[string]string{
+				"KITTY_WINDOW_ID": "1",
+				"TMUX":            "/tmp/tmux-1000/default,1234,0",
+				"TERM":            "termcolor-8",
+				"TERMINFO":        "testdata/terminfo",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level:  LevelBasic,
+				Italic: Unsupported,
+			},
+		},
+		"unknown terminal": {
+			envs: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			isTerminal: true,
+			wanted: Profile{
+				Level: Level256,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+				WithCommandRunner(nil),
+			)
+
+			// When
+			p := d.Capabilities(os.Stdout)
+
+			// Then
+			if p != tc.wanted {
+				t.Errorf("expected %+v, got %+v", tc.wanted, p)
+			}
+		})
+	}
+}
+
+func TestProfile_Support(t *testing.T) {
+	p := Profile{Hyperlinks: Supported, BoxDrawing: Unsupported}
+
+	if got := p.Support(FeatureHyperlinks); got != Supported {
+		t.Errorf("expected %v, got %v", Supported, got)
+	}
+	if got := p.Support(FeatureBoxDrawing); got != Unsupported {
+		t.Errorf("expected %v, got %v", Unsupported, got)
+	}
+	if got := p.Support(FeatureItalic | FeatureHyperlinks); got != SupportUnknown {
+		t.Errorf("expected %v, got %v", SupportUnknown, got)
+	}
+}
@@ -0,0 +1,154 @@
+package termcolor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CIProvider describes a continuous integration system and the color level of its build logs.
+type CIProvider struct {
+	// Name identifies the provider, such as "GitHub Actions".
+	Name string
+	// Env lists the environment variables that are all set when running on the provider.
+	// An entry of the form "NAME=value" also requires the variable to have that exact value.
+	Env []string
+	// VersionEnv is the environment variable that holds the version of the provider, checked against Constraint.
+	VersionEnv string
+	// Constraint is a version constraint such as ">=9.1" that the provider must satisfy for its logs to support
+	// Level. Older versions don't support colors. An empty constraint is satisfied by any version.
+	Constraint string
+	// Level is the color level of the logs.
+	Level Level
+	// Hyperlinks is true if the log viewer renders OSC 8 hyperlinks.
+	Hyperlinks bool
+
+	constraint constraint
+}
+
+// ciProviders holds the registered providers, in the order they're checked.
+var ciProviders = mustCIProviders(
+	CIProvider{
+		Name:       "TeamCity",
+		Env:        []string{"TEAMCITY_VERSION"},
+		VersionEnv: "TEAMCITY_VERSION",
+		Constraint: ">=9.1",
+		Level:      LevelBasic,
+	},
+	CIProvider{Name: "GitHub Actions", Env: []string{"GITHUB_ACTIONS"}, Level: Level16M},
+	CIProvider{Name: "Gitea Actions", Env: []string{"GITEA_ACTIONS"}, Level: Level16M},
+	CIProvider{Name: "Azure Pipelines", Env: []string{"TF_BUILD", "AGENT_NAME"}, Level: LevelBasic},
+	CIProvider{Name: "Jenkins", Env: []string{"JENKINS_URL", "BUILD_ID"}, Level: LevelNone},
+	CIProvider{Name: "Bitbucket Pipelines", Env: []string{"BITBUCKET_BUILD_NUMBER"}, Level: LevelBasic},
+	CIProvider{Name: "Buildkite", Env: []string{"BUILDKITE"}, Level: Level256, Hyperlinks: true},
+	CIProvider{Name: "Netlify", Env: []string{"NETLIFY"}, Level: LevelBasic, Hyperlinks: true},
+	CIProvider{Name: "Woodpecker", Env: []string{"CI=woodpecker"}, Level: LevelBasic},
+	CIProvider{Name: "Drone", Env: []string{"DRONE"}, Level: LevelBasic},
+	CIProvider{Name: "Semaphore", Env: []string{"SEMAPHORE"}, Level: Level256},
+	CIProvider{Name: "Travis CI", Env: []string{"CI", "TRAVIS"}, Level: LevelBasic},
+	CIProvider{Name: "CircleCI", Env: []string{"CI", "CIRCLECI"}, Level: LevelBasic},
+	CIProvider{Name: "AppVeyor", Env: []string{"CI", "APPVEYOR"}, Level: LevelBasic},
+	CIProvider{Name: "GitLab CI", Env: []string{"CI", "GITLAB_CI"}, Level: LevelBasic},
+	CIProvider{Name: "Codeship", Env: []string{"CI", "CI_NAME=codeship"}, Level: LevelBasic},
+)
+
+// RegisterCIProvider adds a provider to the ones recognized by detectors, such as an internal build system.
+// Registered providers are checked before the built-in ones, the last registered first, so they can override them.
+// RegisterCIProvider is meant to be called from init functions: it isn't safe to call it concurrently with
+// detection. It panics if the provider has no name or environment variables, or if its constraint is invalid.
+func RegisterCIProvider(p CIProvider) {
+	p = mustCIProvider(p)
+	ciProviders = append([]CIProvider{p}, ciProviders...)
+}
+
+// CIProviders returns the registered providers in the order they're checked.
+func CIProviders() []CIProvider {
+	return append([]CIProvider(nil), ciProviders...)
+}
+
+func mustCIProviders(providers ...CIProvider) []CIProvider {
+	for i, p := range providers {
+		providers[i] = mustCIProvider(p)
+	}
+	return providers
+}
+
+func mustCIProvider(p CIProvider) CIProvider {
+	if p.Name == "" {
+		panic("termcolor: CI provider has no name")
+	}
+	if len(p.Env) == 0 {
+		panic(fmt.Sprintf("termcolor: CI provider %q has no environment variables", p.Name))
+	}
+	c, err := parseConstraint(p.Constraint)
+	if err != nil {
+		panic(fmt.Sprintf("termcolor: CI provider %q: %v", p.Name, err))
+	}
+	p.constraint = c
+	return p
+}
+
+// CI returns the continuous integration system that the detector's environment runs on.
+// If no registered provider matches, then returns false.
+func (d *Detector) CI() (CIProvider, bool) {
+	e := detection{d: d}
+	return findCI(e.lookupEnv)
+}
+
+// findCI returns the first registered provider whose environment variables are set.
+func findCI(lookupEnv func(key string) (string, bool)) (CIProvider, bool) {
+	for _, p := range ciProviders {
+		if p.matches(lookupEnv) {
+			return p, true
+		}
+	}
+	return CIProvider{}, false
+}
+
+// matches returns true if all the environment variables of the provider are set.
+func (p CIProvider) matches(lookupEnv func(key string) (string, bool)) bool {
+	return envMatches(p.Env, lookupEnv)
+}
+
+// envMatches returns true if all the environment variables are set.
+// An entry of the form "NAME=value" also requires the variable to have that exact value.
+func envMatches(env []string, lookupEnv func(key string) (string, bool)) bool {
+	for _, entry := range env {
+		key, wanted, hasValue := entry, "", false
+		if i := strings.Index(entry, "="); i != -1 {
+			key, wanted, hasValue = entry[:i], entry[i+1:], true
+		}
+		v, ok := lookupEnv(key)
+		if !ok || (hasValue && v != wanted) {
+			return false
+		}
+	}
+	return true
+}
+
+// level returns the color level of the provider's logs given the version found in its environment.
+func (p CIProvider) level(lookupEnv func(key string) (string, bool)) Level {
+	if p.VersionEnv == "" {
+		return p.Level
+	}
+	raw, _ := lookupEnv(p.VersionEnv)
+	v, ok := parseVersion(raw)
+	if !ok || !p.constraint.check(v) {
+		return LevelNone
+	}
+	return p.Level
+}
+
+// lookupCI returns the color level of the continuous integration system's logs, but never less than min.
+// If the environment isn't a CI, then returns false.
+func (e *detection) lookupCI(min Level) (Level, bool) {
+	lookupEnv := e.onceLookupEnv()
+	p, ok := findCI(lookupEnv)
+	if !ok {
+		// Other CI products set the env CI=true.
+		if _, isCI := lookupEnv("CI"); !isCI {
+			return LevelNone, false
+		}
+		return min, true
+	}
+	return atLeast(p.level(lookupEnv), min), true
+}
@@ -0,0 +1,161 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetector_CI(t *testing.T) {
+	testCases := map[string]struct {
+		envs map[string]string
+
+		wantedName  string
+		wantedOK    bool
+		wantedLevel Level
+	}{
+		"not a CI": {
+			envs:        map[string]string{},
+			wantedLevel: LevelNone,
+		},
+		"unknown CI": {
+			envs:        map[string]string{"CI": "true"},
+			wantedLevel: LevelNone,
+		},
+		"github actions": {
+			envs:        map[string]string{"CI": "true", "GITHUB_ACTIONS": "true"},
+			wantedName:  "GitHub Actions",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"buildkite": {
+			envs:        map[string]string{"CI": "true", "BUILDKITE": "true"},
+			wantedName:  "Buildkite",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+		"azure pipelines": {
+			envs:        map[string]string{"TF_BUILD": "True", "AGENT_NAME": "Hosted Agent"},
+			wantedName:  "Azure Pipelines",
+			wantedOK:    true,
+			wantedLevel: LevelBasic,
+		},
+		"jenkins": {
+			envs:        map[string]string{"JENKINS_URL": "https://ci.example.com/", "BUILD_ID": "42"},
+			wantedName:  "Jenkins",
+			wantedOK:    true,
+			wantedLevel: LevelNone,
+		},
+		"woodpecker": {
+			envs:        map[string]string{"CI": "woodpecker"},
+			wantedName:  "Woodpecker",
+			wantedOK:    true,
+			wantedLevel: LevelBasic,
+		},
+		"codeship requires its name": {
+			envs:        map[string]string{"CI": "true", "CI_NAME": "other"},
+			wantedLevel: LevelNone,
+		},
+		"old teamcity": {
+			envs:        map[string]string{"TEAMCITY_VERSION": "8.1"},
+			wantedName:  "TeamCity",
+			wantedOK:    true,
+			wantedLevel: LevelNone,
+		},
+		"forced colors are not lowered by the CI": {
+			envs:        map[string]string{"JENKINS_URL": "https://ci.example.com/", "BUILD_ID": "42", "FORCE_COLOR": "2"},
+			wantedName:  "Jenkins",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return true
+				}),
+				WithTerminfoDirs(),
+			)
+
+			// When
+			p, ok := d.CI()
+			l := d.Level(os.Stdout)
+
+			// Then
+			if ok != tc.wantedOK {
+				t.Fatalf("expected ok %v, got %v", tc.wantedOK, ok)
+			}
+			if p.Name != tc.wantedName {
+				t.Errorf("expected provider %q, got %q", tc.wantedName, p.Name)
+			}
+			if l != tc.wantedLevel {
+				t.Errorf("expected level %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestRegisterCIProvider(t *testing.T) {
+	// Given
+	saved := ciProviders
+	defer func() {
+		ciProviders = saved
+	}()
+	RegisterCIProvider(CIProvider{
+		Name:       "Internal CI",
+		Env:        []string{"CI", "INTERNAL_CI"},
+		VersionEnv: "INTERNAL_CI",
+		Constraint: ">=2",
+		Level:      Level16M,
+	})
+	d := NewDetector(
+		WithArgs(nil),
+		WithEnv(map[string]string{"CI": "true", "INTERNAL_CI": "2.3", "GITLAB_CI": "true"}),
+		WithTerminalChecker(func(fd uintptr) bool {
+			return true
+		}),
+		WithTerminfoDirs(),
+	)
+
+	// When
+	p, ok := d.CI()
+	l := d.Level(os.Stdout)
+
+	// Then
+	if !ok || p.Name != "Internal CI" {
+		t.Errorf("expected the registered provider to take precedence, got %q", p.Name)
+	}
+	if l != Level16M {
+		t.Errorf("expected %v, got %v", Level16M, l)
+	}
+	if providers := CIProviders(); providers[0].Name != "Internal CI" {
+		t.Errorf("expected the registered provider first, got %q", providers[0].Name)
+	}
+}
+
+func TestRegisterCIProvider_Panics(t *testing.T) {
+	testCases := map[string]CIProvider{
+		"no name":            {Env: []string{"X"}},
+		"no env":             {Name: "X"},
+		"invalid constraint": {Name: "X", Env: []string{"X"}, Constraint: ">=x"},
+	}
+
+	for name, p := range testCases {
+		p := p
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+			mustCIProvider(p)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+// Command termcolor prints the color support detected for the standard output and error, along with the rules,
+// terminal emulator, CI provider and multiplexer that determined it.
+//
+// Usage:
+//
+//	termcolor [--json] [--preview]
+//
+// The --json flag prints the report as JSON, and the --preview flag renders swatches of the basic 16 colors, the
+// 256 colors palette and true colors regardless of the detected level, to confirm what the terminal really shows.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/efekarakus/termcolor"
+)
+
+func main() {
+	a := app{
+		// The flags of the command aren't color flags.
+		detector: termcolor.NewDetector(termcolor.WithArgSniffing(false)),
+		out:      os.Stdout,
+		stdout:   os.Stdout,
+		stderr:   os.Stderr,
+	}
+	if err := a.run(os.Args[1:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "termcolor:", err)
+		}
+		os.Exit(2)
+	}
+}
+
+// app holds the dependencies of the command.
+type app struct {
+	detector       *termcolor.Detector
+	out            io.Writer
+	stdout, stderr termcolor.FileDescriptor
+}
+
+// report is the color support of the standard streams and the environment they were detected in.
+type report struct {
+	Stdout      stream `json:"stdout"`
+	Stderr      stream `json:"stderr"`
+	Terminal    string `json:"terminal,omitempty"`
+	CI          string `json:"ci,omitempty"`
+	Multiplexer string `json:"multiplexer"`
+}
+
+// stream is the color support of a file descriptor.
+type stream struct {
+	Level    termcolor.Level `json:"level"`
+	Colors   int             `json:"colors"`
+	Decision string          `json:"-"`
+	Decider  string          `json:"decider,omitempty"`
+	Steps    []step          `json:"steps"`
+}
+
+// step is a rule that was consulted while detecting the color level.
+type step struct {
+	Rule    string           `json:"rule"`
+	Level   *termcolor.Level `json:"level,omitempty"`
+	Inputs  []string         `json:"inputs,omitempty"`
+	Decided bool             `json:"decided,omitempty"`
+}
+
+func (a app) run(args []string) error {
+	fs := flag.NewFlagSet("termcolor", flag.ContinueOnError)
+	fs.SetOutput(a.out)
+	asJSON := fs.Bool("json", false, "print the report as JSON")
+	preview := fs.Bool("preview", false, "render color swatches after the report")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	r := a.report()
+	if *asJSON {
+		enc := json.NewEncoder(a.out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		printReport(a.out, r)
+	}
+	if *preview {
+		if !*asJSON {
+			fmt.Fprintln(a.out)
+		}
+		printPreview(a.out)
+	}
+	return nil
+}
+
+func (a app) report() report {
+	r := report{
+		Stdout:      newStream(a.detector.Explain(a.stdout)),
+		Stderr:      newStream(a.detector.Explain(a.stderr)),
+		Multiplexer: a.detector.Multiplexer().String(),
+	}
+	if t, ok := a.detector.Terminal(); ok {
+		r.Terminal = t.Name
+	}
+	if p, ok := a.detector.CI(); ok {
+		r.CI = p.Name
+	}
+	return r
+}
+
+func newStream(d termcolor.Decision) stream {
+	s := stream{
+		Level:    d.Level,
+		Colors:   d.Colors,
+		Decision: d.String(),
+		Steps:    make([]step, 0, len(d.Steps)),
+	}
+	if decider, ok := d.Decider(); ok {
+		s.Decider = decider.Rule
+	}
+	for _, ds := range d.Steps {
+		st := step{Rule: ds.Rule, Decided: ds.Decided}
+		if ds.Decided {
+			l := ds.Level
+			st.Level = &l
+		}
+		for _, in := range ds.Inputs {
+			st.Inputs = append(st.Inputs, in.String())
+		}
+		s.Steps = append(s.Steps, st)
+	}
+	return s
+}
+
+func printReport(w io.Writer, r report) {
+	fmt.Fprintf(w, "stdout: %s\n", indent(r.Stdout.Decision))
+	fmt.Fprintf(w, "stderr: %s\n", indent(r.Stderr.Decision))
+	fmt.Fprintf(w, "terminal: %s\n", orNone(r.Terminal))
+	fmt.Fprintf(w, "ci: %s\n", orNone(r.CI))
+	fmt.Fprintf(w, "multiplexer: %s\n", r.Multiplexer)
+}
+
+// indent indents the lines after the first one of s by two spaces.
+func indent(s string) string {
+	return strings.Replace(s, "\n", "\n  ", -1)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// swatch is the text rendered with each background color of the preview.
+const swatch = "  "
+
+// printPreview renders the colors with the sequences of their own palette, regardless of the detected level.
+func printPreview(w io.Writer) {
+	fmt.Fprintln(w, "16 colors:")
+	for row := 0; row < 2; row++ {
+		var b strings.Builder
+		for i := 0; i < 8; i++ {
+			c := termcolor.BasicColor(row*8 + i)
+			b.WriteString(termcolor.Style{Background: c}.Render(swatch, termcolor.Level16M))
+		}
+		fmt.Fprintln(w, b.String())
+	}
+
+	fmt.Fprintln(w, "256 colors:")
+	for row := 0; row < 6; row++ {
+		var b strings.Builder
+		for i := 0; i < 36; i++ {
+			c := termcolor.Color256(16 + row*36 + i)
+			b.WriteString(termcolor.Style{Background: c}.Render(" ", termcolor.Level16M))
+		}
+		fmt.Fprintln(w, b.String())
+	}
+	var grays strings.Builder
+	for i := 232; i < 256; i++ {
+		grays.WriteString(termcolor.Style{Background: termcolor.Color256(i)}.Render(" ", termcolor.Level16M))
+	}
+	fmt.Fprintln(w, grays.String())
+
+	fmt.Fprintln(w, "true colors:")
+	const width = 72
+	var b strings.Builder
+	for i := 0; i < width; i++ {
+		c := hue(float64(i) / width)
+		b.WriteString(termcolor.Style{Background: c}.Render(" ", termcolor.Level16M))
+	}
+	fmt.Fprintln(w, b.String())
+	fmt.Fprintln(w, "The true colors should blend into a smooth gradient. Visible bands mean that the terminal replaces them with a palette.")
+}
+
+// hue returns the fully saturated color at the position h of the color wheel, between 0 and 1.
+func hue(h float64) termcolor.RGB {
+	channel := func(offset float64) uint8 {
+		v := math.Abs(math.Mod(h*6+offset, 6)-3) - 1
+		v = math.Max(0, math.Min(1, v))
+		return uint8(math.Round(v * 255))
+	}
+	return termcolor.RGB{R: channel(0), G: channel(4), B: channel(2)}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/efekarakus/termcolor"
+)
+
+func newTestApp(out *bytes.Buffer) app {
+	return app{
+		detector: termcolor.NewDetector(
+			termcolor.WithArgSniffing(false),
+			termcolor.WithEnv(map[string]string{
+				"TERM":            "xterm-256color",
+				"KITTY_WINDOW_ID": "1",
+				"GITHUB_ACTIONS":  "true",
+			}),
+			termcolor.WithTerminalChecker(func(fd uintptr) bool {
+				// Only stdout is a terminal.
+				return fd == 1
+			}),
+			termcolor.WithTerminfoDirs(),
+			termcolor.WithCommandRunner(nil),
+		),
+		out:    out,
+		stdout: os.Stdout,
+		stderr: os.Stderr,
+	}
+}
+
+func TestApp_Run(t *testing.T) {
+	// Given
+	var out bytes.Buffer
+	a := newTestApp(&out)
+
+	// When
+	err := a.run(nil)
+
+	// Then
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, wanted := range []string{
+		"stdout: level 16m decided by lookupCI\n",
+		"stderr: level none decided by isTerminal\n",
+		"terminal: kitty\n",
+		"ci: GitHub Actions\n",
+		"multiplexer: none\n",
+	} {
+		if !strings.Contains(out.String(), wanted) {
+			t.Errorf("expected the report to contain %q, got:\n%s", wanted, out.String())
+		}
+	}
+}
+
+func TestApp_RunJSON(t *testing.T) {
+	// Given
+	var out bytes.Buffer
+	a := newTestApp(&out)
+
+	// When
+	err := a.run([]string{"--json"})
+
+	// Then
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var r report
+	if err := json.Unmarshal(out.Bytes(), &r); err != nil {
+		t.Fatalf("expected valid JSON, got %v:\n%s", err, out.String())
+	}
+	if r.Stdout.Level != termcolor.Level16M || r.Stdout.Colors != 1<<24 || r.Stdout.Decider != "lookupCI" {
+		t.Errorf("expected stdout to be decided by lookupCI with true colors, got %+v", r.Stdout)
+	}
+	if r.Stderr.Level != termcolor.LevelNone {
+		t.Errorf("expected stderr to have no colors, got %v", r.Stderr.Level)
+	}
+	if r.Terminal != "kitty" || r.CI != "GitHub Actions" || r.Multiplexer != "none" {
+		t.Errorf("expected kitty on GitHub Actions without multiplexer, got %+v", r)
+	}
+	last := r.Stdout.Steps[len(r.Stdout.Steps)-1]
+	if !last.Decided || last.Level == nil || *last.Level != termcolor.Level16M {
+		t.Errorf("expected the last step to decide true colors, got %+v", last)
+	}
+}
+
+func TestApp_RunPreview(t *testing.T) {
+	// Given
+	var out bytes.Buffer
+	a := newTestApp(&out)
+
+	// When
+	err := a.run([]string{"--preview"})
+
+	// Then
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	for _, wanted := range []string{"\x1b[101m", "\x1b[48;5;196m", "\x1b[48;2;255;0;0m"} {
+		if !strings.Contains(out.String(), wanted) {
+			t.Errorf("expected the preview to contain %q", wanted)
+		}
+	}
+}
+
+func TestApp_RunUnexpectedArgument(t *testing.T) {
+	// Given
+	var out bytes.Buffer
+	a := newTestApp(&out)
+
+	// When
+	err := a.run([]string{"extra"})
+
+	// Then
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,279 @@
+package termcolor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// RGB is a 24-bit color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// Hex returns the color in the "#rrggbb" format.
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// IsDark returns true if white text is more readable than black text on the color.
+// The comparison uses the relative luminance of the color as defined by WCAG 2.
+func (c RGB) IsDark() bool {
+	// Black and white have the same contrast ratio with a color of luminance sqrt(1.05 * 0.05) - 0.05.
+	return c.luminance() < 0.179
+}
+
+// luminance returns the relative luminance of the color between 0 for black and 1 for white.
+// See https://www.w3.org/TR/WCAG21/#dfn-relative-luminance
+func (c RGB) luminance() float64 {
+	return 0.2126*linearize(c.R) + 0.7152*linearize(c.G) + 0.0722*linearize(c.B)
+}
+
+// linearize converts an sRGB component to linear light.
+func linearize(v uint8) float64 {
+	f := float64(v) / 255
+	if f <= 0.04045 {
+		return f / 12.92
+	}
+	return math.Pow((f+0.055)/1.055, 2.4)
+}
+
+// basicPalette holds xterm's default values for the 16 basic colors.
+var basicPalette = [16]RGB{
+	{0, 0, 0},
+	{205, 0, 0},
+	{0, 205, 0},
+	{205, 205, 0},
+	{0, 0, 238},
+	{205, 0, 205},
+	{0, 205, 205},
+	{229, 229, 229},
+	{127, 127, 127},
+	{255, 0, 0},
+	{0, 255, 0},
+	{255, 255, 0},
+	{92, 92, 255},
+	{255, 0, 255},
+	{0, 255, 255},
+	{255, 255, 255},
+}
+
+// Color is a foreground or background color that can be rendered by a Style.
+// It's implemented by BasicColor, Color88, Color256 and RGB.
+type Color interface {
+	// sgr returns the SGR parameters that select the color at the level, or "" if the level has no colors.
+	sgr(l Level, background bool) string
+}
+
+// BasicColor is one of the 16 basic colors. Their actual values depend on the palette of the terminal.
+type BasicColor uint8
+
+// The basic colors.
+const (
+	Black BasicColor = iota
+	Red
+	Green
+	Yellow
+	Blue
+	Magenta
+	Cyan
+	White
+	BrightBlack
+	BrightRed
+	BrightGreen
+	BrightYellow
+	BrightBlue
+	BrightMagenta
+	BrightCyan
+	BrightWhite
+)
+
+func (c BasicColor) sgr(l Level, background bool) string {
+	if l == LevelNone {
+		return ""
+	}
+	c &= 15
+	if !l.AtLeast(LevelBasic) && c >= BrightBlack {
+		// Terminals with 8 colors ignore the bright colors.
+		c -= BrightBlack
+	}
+	base := 30
+	if background {
+		base = 40
+	}
+	if c >= BrightBlack {
+		// aixterm's bright colors.
+		return strconv.Itoa(base + 60 + int(c-BrightBlack))
+	}
+	return strconv.Itoa(base + int(c))
+}
+
+// Color256 is an index in the xterm 256 colors palette: the 16 basic colors, followed by a 6x6x6 color cube
+// and a grayscale ramp of 24 shades.
+type Color256 uint8
+
+// RGB returns xterm's default value of the color.
+func (c Color256) RGB() RGB {
+	switch {
+	case c < 16:
+		return basicPalette[c]
+	case c < 232:
+		i := int(c) - 16
+		return RGB{cubeLevels[i/36], cubeLevels[i/6%6], cubeLevels[i%6]}
+	default:
+		v := uint8(8 + 10*(int(c)-232))
+		return RGB{v, v, v}
+	}
+}
+
+func (c Color256) sgr(l Level, background bool) string {
+	if !l.AtLeast(Level256) {
+		if converted := l.Convert(c); converted != nil {
+			return converted.sgr(l, background)
+		}
+		return ""
+	}
+	if background {
+		return "48;5;" + strconv.Itoa(int(c))
+	}
+	return "38;5;" + strconv.Itoa(int(c))
+}
+
+// Color88 is an index in rxvt's 88 colors palette: the 16 basic colors, followed by a 4x4x4 color cube
+// and a grayscale ramp of 8 shades. Indexes above 87 are the last shade.
+type Color88 uint8
+
+// RGB returns rxvt's default value of the color.
+func (c Color88) RGB() RGB {
+	switch {
+	case c < 16:
+		return basicPalette[c]
+	case c < 80:
+		i := int(c) - 16
+		return RGB{cubeLevels88[i/16], cubeLevels88[i/4%4], cubeLevels88[i%4]}
+	case c < 88:
+		v := grayLevels88[c-80]
+		return RGB{v, v, v}
+	default:
+		v := grayLevels88[len(grayLevels88)-1]
+		return RGB{v, v, v}
+	}
+}
+
+func (c Color88) sgr(l Level, background bool) string {
+	if l != Level88 {
+		if converted := l.Convert(c); converted != nil {
+			return converted.sgr(l, background)
+		}
+		return ""
+	}
+	if c > 87 {
+		c = 87
+	}
+	if background {
+		return "48;5;" + strconv.Itoa(int(c))
+	}
+	return "38;5;" + strconv.Itoa(int(c))
+}
+
+func (c RGB) sgr(l Level, background bool) string {
+	if !l.AtLeast(Level16M) {
+		if converted := l.Convert(c); converted != nil {
+			return converted.sgr(l, background)
+		}
+		return ""
+	}
+	prefix := "38;2;"
+	if background {
+		prefix = "48;2;"
+	}
+	return prefix + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+}
+
+// cubeLevels are the values of each component in the 6x6x6 color cube of the 256 colors palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// cubeLevels88 and grayLevels88 are the values of the 4x4x4 color cube and grayscale ramp of the 88 colors palette.
+var (
+	cubeLevels88 = [4]uint8{0, 139, 205, 255}
+	grayLevels88 = [8]uint8{46, 92, 115, 139, 162, 185, 208, 231}
+)
+
+// NearestBasic returns the basic color that looks the closest to c with xterm's default palette.
+// Colors are compared in the OKLab perceptual color space.
+func NearestBasic(c RGB) BasicColor {
+	return BasicColor(nearest(c.oklab(), basicPaletteLab[:]))
+}
+
+// Nearest256 returns the color of the 6x6x6 cube or grayscale ramp of the 256 colors palette that looks the
+// closest to c. The first 16 colors of the palette are never returned since terminals customize their values.
+// Colors are compared in the OKLab perceptual color space.
+func Nearest256(c RGB) Color256 {
+	return Color256(16 + nearest(c.oklab(), extendedPaletteLab[:]))
+}
+
+// Nearest88 returns the color of the 4x4x4 cube or grayscale ramp of the 88 colors palette that looks the
+// closest to c. The first 16 colors of the palette are never returned since terminals customize their values.
+// Colors are compared in the OKLab perceptual color space.
+func Nearest88(c RGB) Color88 {
+	return Color88(16 + nearest(c.oklab(), palette88Lab[:]))
+}
+
+// nearest8 returns the basic color without the bright ones that looks the closest to c.
+func nearest8(c RGB) BasicColor {
+	return BasicColor(nearest(c.oklab(), basicPaletteLab[:8]))
+}
+
+// nearest returns the index of the color closest to c in palette.
+func nearest(c oklab, palette []oklab) int {
+	best, bestDist := 0, math.Inf(1)
+	for i, p := range palette {
+		if d := c.distance(p); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+// oklab is a color in the OKLab perceptual color space, where euclidean distances match perceived differences.
+// See https://bottosson.github.io/posts/oklab/
+type oklab struct {
+	L, A, B float64
+}
+
+func (c RGB) oklab() oklab {
+	r, g, b := linearize(c.R), linearize(c.G), linearize(c.B)
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+	return oklab{
+		L: 0.2104542553*l + 0.7936177850*m - 0.0040720468*s,
+		A: 1.9779984951*l - 2.4285922050*m + 0.4505937099*s,
+		B: 0.0259040371*l + 0.7827717662*m - 0.8086757660*s,
+	}
+}
+
+// distance returns the squared euclidean distance between two colors.
+func (c oklab) distance(o oklab) float64 {
+	dl, da, db := c.L-o.L, c.A-o.A, c.B-o.B
+	return dl*dl + da*da + db*db
+}
+
+// Palettes converted to OKLab once to speed up searches.
+var (
+	basicPaletteLab    [16]oklab
+	extendedPaletteLab [240]oklab
+	palette88Lab       [72]oklab
+)
+
+func init() {
+	for i, c := range basicPalette {
+		basicPaletteLab[i] = c.oklab()
+	}
+	for i := range extendedPaletteLab {
+		extendedPaletteLab[i] = Color256(16 + i).RGB().oklab()
+	}
+	for i := range palette88Lab {
+		palette88Lab[i] = Color88(16 + i).RGB().oklab()
+	}
+}
@@ -0,0 +1,163 @@
+package termcolor
+
+import "testing"
+
+func TestRGB_IsDark(t *testing.T) {
+	testCases := map[string]struct {
+		color  RGB
+		wanted bool
+	}{
+		"black":                 {color: RGB{0, 0, 0}, wanted: true},
+		"solarized dark base03": {color: RGB{0, 43, 54}, wanted: true},
+		"xterm blue":            {color: RGB{0, 0, 238}, wanted: true},
+		"white":                 {color: RGB{255, 255, 255}},
+		"solarized light base3": {color: RGB{253, 246, 227}},
+		"xterm green":           {color: RGB{0, 205, 0}},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.color.IsDark(); got != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestRGB_Hex(t *testing.T) {
+	if got := (RGB{25, 255, 203}).Hex(); got != "#19ffcb" {
+		t.Errorf("expected #19ffcb, got %s", got)
+	}
+}
+
+func TestNearest256(t *testing.T) {
+	testCases := map[string]struct {
+		color  RGB
+		wanted Color256
+	}{
+		"aquamarine":    {color: RGB{25, 255, 203}, wanted: 50},
+		"orange":        {color: RGB{255, 128, 0}, wanted: 208},
+		"cornflower":    {color: RGB{100, 149, 237}, wanted: 68},
+		"dark navy":     {color: RGB{30, 30, 46}, wanted: 234},
+		"gray in ramp":  {color: RGB{128, 128, 128}, wanted: 244},
+		"white in cube": {color: RGB{255, 255, 255}, wanted: 231},
+		"black in cube": {color: RGB{0, 0, 0}, wanted: 16},
+		"near pure red": {color: RGB{250, 5, 0}, wanted: 196},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Nearest256(tc.color); got != tc.wanted {
+				t.Errorf("expected %d, got %d", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestNearest256_PaletteColorsMapToThemselves(t *testing.T) {
+	for i := 16; i < 256; i++ {
+		c := Color256(i)
+		if got := Nearest256(c.RGB()); got != c {
+			t.Errorf("expected %v to map to %d, got %d", c.RGB(), c, got)
+		}
+	}
+}
+
+func TestNearest88_PaletteColorsMapToThemselves(t *testing.T) {
+	for i := 16; i < 88; i++ {
+		c := Color88(i)
+		if i == 83 {
+			// The gray 139 is also in the cube.
+			continue
+		}
+		if got := Nearest88(c.RGB()); got != c {
+			t.Errorf("expected %v to map to %d, got %d", c.RGB(), c, got)
+		}
+	}
+}
+
+func TestNearestBasic(t *testing.T) {
+	testCases := map[string]struct {
+		color  RGB
+		wanted BasicColor
+	}{
+		"chartreuse is green, not yellow": {color: RGB{135, 255, 0}, wanted: BrightGreen},
+		"aquamarine":                      {color: RGB{25, 255, 203}, wanted: BrightCyan},
+		"cornflower":                      {color: RGB{100, 149, 237}, wanted: BrightBlue},
+		"dark navy":                       {color: RGB{30, 30, 46}, wanted: Black},
+		"brick red":                       {color: RGB{200, 30, 30}, wanted: Red},
+		"mid gray":                        {color: RGB{128, 128, 128}, wanted: BrightBlack},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := NearestBasic(tc.color); got != tc.wanted {
+				t.Errorf("expected %d, got %d", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestLevel_Convert(t *testing.T) {
+	testCases := map[string]struct {
+		level Level
+		color Color
+
+		wanted Color
+	}{
+		"no colors":                    {level: LevelNone, color: Red, wanted: nil},
+		"rgb at 16M":                   {level: Level16M, color: RGB{1, 2, 3}, wanted: RGB{1, 2, 3}},
+		"rgb at 256":                   {level: Level256, color: RGB{255, 128, 0}, wanted: Color256(208)},
+		"rgb at basic":                 {level: LevelBasic, color: RGB{135, 255, 0}, wanted: BrightGreen},
+		"256 color at 256":             {level: Level256, color: Color256(208), wanted: Color256(208)},
+		"256 color at basic":           {level: LevelBasic, color: Color256(118), wanted: BrightGreen},
+		"256 color in the basic range": {level: LevelBasic, color: Color256(4), wanted: Blue},
+		"basic color at basic":         {level: LevelBasic, color: Magenta, wanted: Magenta},
+		"bright color at 8":            {level: Level8, color: BrightMagenta, wanted: Magenta},
+		"rgb at 8":                     {level: Level8, color: RGB{30, 200, 40}, wanted: Green},
+		"256 color at 8":               {level: Level8, color: Color256(9), wanted: Red},
+		"rgb at 88":                    {level: Level88, color: RGB{255, 128, 0}, wanted: Color88(68)},
+		"256 color at 88":              {level: Level88, color: Color256(196), wanted: Color88(64)},
+		"88 color at 88":               {level: Level88, color: Color88(68), wanted: Color88(68)},
+		"88 color at 256":              {level: Level256, color: Color88(68), wanted: Color256(208)},
+		"88 color at 16M":              {level: Level16M, color: Color88(80), wanted: RGB{46, 46, 46}},
+		"88 color in the basic range":  {level: LevelBasic, color: Color88(12), wanted: BrightBlue},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.level.Convert(tc.color); got != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestLevel_Colors(t *testing.T) {
+	testCases := map[Level]int{
+		LevelNone:  0,
+		Level8:     8,
+		LevelBasic: 16,
+		Level88:    88,
+		Level256:   256,
+		Level16M:   1 << 24,
+	}
+
+	for level, wanted := range testCases {
+		if got := level.Colors(); got != wanted {
+			t.Errorf("expected level %d to have %d colors, got %d", level, wanted, got)
+		}
+	}
+}
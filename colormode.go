@@ -0,0 +1,119 @@
+package termcolor
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// ColorMode is the value of a --color option. It implements flag.Value and encoding.TextUnmarshaler so it can be
+// registered on a flag.FlagSet or decoded from configuration files.
+type ColorMode int
+
+// Color modes.
+const (
+	// ColorModeAuto lets the detector determine the level.
+	ColorModeAuto ColorMode = iota
+	// ColorModeAlways enables at least the basic colors, even when the output isn't a terminal.
+	ColorModeAlways
+	// ColorModeNever disables colors.
+	ColorModeNever
+	// ColorMode256 forces 256 colors.
+	ColorMode256
+	// ColorMode16M forces true colors.
+	ColorMode16M
+)
+
+// colorModeNames maps the accepted values of a --color option to color modes.
+var colorModeNames = map[string]ColorMode{
+	"auto":      ColorModeAuto,
+	"always":    ColorModeAlways,
+	"true":      ColorModeAlways,
+	"yes":       ColorModeAlways,
+	"force":     ColorModeAlways,
+	"never":     ColorModeNever,
+	"false":     ColorModeNever,
+	"no":        ColorModeNever,
+	"none":      ColorModeNever,
+	"256":       ColorMode256,
+	"16m":       ColorMode16M,
+	"truecolor": ColorMode16M,
+	"full":      ColorMode16M,
+}
+
+// String returns the name of the mode, such as "auto" or "16m".
+func (m ColorMode) String() string {
+	switch m {
+	case ColorModeAuto:
+		return "auto"
+	case ColorModeAlways:
+		return "always"
+	case ColorModeNever:
+		return "never"
+	case ColorMode256:
+		return "256"
+	case ColorMode16M:
+		return "16m"
+	default:
+		return fmt.Sprintf("ColorMode(%d)", int(m))
+	}
+}
+
+// Set parses s as one of auto, always, never, 256 or 16m, case insensitively.
+// The aliases true, yes and force stand for always, false, no and none for never, and truecolor and full for 16m.
+func (m *ColorMode) Set(s string) error {
+	mode, ok := colorModeNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return fmt.Errorf("termcolor: invalid color mode %q, expected auto, always, never, 256 or 16m", s)
+	}
+	*m = mode
+	return nil
+}
+
+// UnmarshalText parses text like Set.
+func (m *ColorMode) UnmarshalText(text []byte) error {
+	return m.Set(string(text))
+}
+
+// MarshalText returns the name of the mode.
+func (m ColorMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// RegisterFlags registers the mode on fs as the --color option and its --colour alias.
+func (m *ColorMode) RegisterFlags(fs *flag.FlagSet) {
+	const usage = "when to use colors: auto, always, never, 256 or 16m"
+	fs.Var(m, "color", usage)
+	fs.Var(m, "colour", usage)
+}
+
+// WithColorMode makes the detector read the color flags from mode instead of scanning the command line arguments.
+// The mode is read on every detection, so it can be registered on a flag.FlagSet that's parsed after the detector
+// is created.
+func WithColorMode(mode *ColorMode) DetectorOption {
+	return func(d *Detector) {
+		d.mode = mode
+	}
+}
+
+// WithArgSniffing sets whether the detector scans the command line arguments for flags such as --no-color.
+// It's enabled by default.
+func WithArgSniffing(enabled bool) DetectorOption {
+	return func(d *Detector) {
+		d.sniffArgs = enabled
+	}
+}
+
+// colorMode returns the mode set with WithColorMode. If no mode was set, then returns false.
+func (e *detection) colorMode() (ColorMode, bool) {
+	if e.d.mode == nil {
+		return ColorModeAuto, false
+	}
+	m := *e.d.mode
+	e.record(Input{
+		Source:  SourceFlag,
+		Name:    "--color=" + m.String(),
+		Present: m != ColorModeAuto,
+	})
+	return m, true
+}
@@ -0,0 +1,200 @@
+package termcolor
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestColorMode_Set(t *testing.T) {
+	testCases := map[string]struct {
+		value string
+
+		wanted    ColorMode
+		wantedErr bool
+	}{
+		"auto":      {value: "auto", wanted: ColorModeAuto},
+		"always":    {value: "always", wanted: ColorModeAlways},
+		"never":     {value: "never", wanted: ColorModeNever},
+		"256":       {value: "256", wanted: ColorMode256},
+		"16m":       {value: "16m", wanted: ColorMode16M},
+		"uppercase": {value: "NEVER", wanted: ColorModeNever},
+		"true":      {value: "true", wanted: ColorModeAlways},
+		"truecolor": {value: "truecolor", wanted: ColorMode16M},
+		"invalid":   {value: "sometimes", wantedErr: true},
+		"empty":     {value: "", wantedErr: true},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			var m ColorMode
+
+			// When
+			err := m.UnmarshalText([]byte(tc.value))
+
+			// Then
+			if tc.wantedErr {
+				if err == nil {
+					t.Errorf("expected an error, got mode %v", m)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if m != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, m)
+			}
+		})
+	}
+}
+
+func TestColorMode_RegisterFlags(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+
+		wanted ColorMode
+	}{
+		"no flag":               {args: []string{}, wanted: ColorModeAuto},
+		"value in the same arg": {args: []string{"--color=256"}, wanted: ColorMode256},
+		"value in the next arg": {args: []string{"--color", "never"}, wanted: ColorModeNever},
+		"single dash":           {args: []string{"-color", "16m"}, wanted: ColorMode16M},
+		"british spelling":      {args: []string{"--colour=always"}, wanted: ColorModeAlways},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+			fs.SetOutput(ioutil.Discard)
+			var m ColorMode
+			m.RegisterFlags(fs)
+
+			// When
+			err := fs.Parse(tc.args)
+
+			// Then
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if m != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, m)
+			}
+		})
+	}
+}
+
+func TestWithColorMode(t *testing.T) {
+	testCases := map[string]struct {
+		mode       ColorMode
+		args       []string
+		envs       map[string]string
+		isTerminal bool
+
+		wantedLevel Level
+	}{
+		"auto uses the environment": {
+			mode:        ColorModeAuto,
+			envs:        map[string]string{"TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"never takes priority over the environment": {
+			mode:       ColorModeNever,
+			envs:       map[string]string{"FORCE_COLOR": "3"},
+			isTerminal: true,
+		},
+		"always when not a terminal": {
+			mode:        ColorModeAlways,
+			envs:        map[string]string{"TERM": "xterm-256color"},
+			wantedLevel: Level256,
+		},
+		"always takes priority over NO_COLOR": {
+			mode:        ColorModeAlways,
+			envs:        map[string]string{"NO_COLOR": "1"},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"256": {
+			mode:        ColorMode256,
+			wantedLevel: Level256,
+		},
+		"16m": {
+			mode:        ColorMode16M,
+			wantedLevel: Level16M,
+		},
+		"arguments are ignored": {
+			mode:        ColorModeAuto,
+			args:        []string{"cli", "--no-color"},
+			envs:        map[string]string{"TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			mode := tc.mode
+			d := NewDetector(
+				WithArgs(tc.args),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+				WithColorMode(&mode),
+			)
+
+			// When
+			l := d.Level(os.Stdout)
+
+			// Then
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestWithArgSniffing(t *testing.T) {
+	// Given
+	d := NewDetector(
+		WithArgs([]string{"cli", "--no-color", "--hyperlink"}),
+		WithEnv(map[string]string{"TERM": "xterm-256color"}),
+		WithTerminalChecker(func(fd uintptr) bool {
+			return true
+		}),
+		WithTerminfoDirs(),
+		WithArgSniffing(false),
+	)
+
+	// When
+	decision := d.Explain(os.Stdout)
+
+	// Then
+	if decision.Level != Level256 {
+		t.Errorf("expected %v, got %v", Level256, decision.Level)
+	}
+	for _, step := range decision.Steps {
+		for _, in := range step.Inputs {
+			if in.Source == SourceFlag {
+				t.Errorf("expected no flag to be looked up, got %v in %s", in, step.Rule)
+			}
+		}
+	}
+	if d.SupportsHyperlinks(os.Stdout) {
+		t.Errorf("expected the --hyperlink flag to be ignored")
+	}
+}
@@ -0,0 +1,95 @@
+package termcolor
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// colorEnvKeys are the environment variables that SetCommandLevel overrides.
+var colorEnvKeys = []string{"FORCE_COLOR", "NO_COLOR", "CLICOLOR_FORCE", "CLICOLOR", "COLORTERM"}
+
+// CommandOption configures how a child process is started by StartCommand.
+type CommandOption func(c *commandConfig)
+
+type commandConfig struct {
+	pty bool
+}
+
+// WithPTY runs the child process under a pseudo-terminal, so that programs that only check whether their output is
+// a terminal still emit colors. The standard output and error of the child are both written to the pseudo-terminal,
+// which is copied to the command's Stdout. It's only supported on Linux.
+func WithPTY() CommandOption {
+	return func(c *commandConfig) {
+		c.pty = true
+	}
+}
+
+// SetCommandLevel sets the environment variables of cmd so that the child process uses the colors of l even if its
+// output is a pipe. If cmd.Env is nil, the environment of the current process is used as a base.
+//
+// LevelNone removes the variables that force colors and sets NO_COLOR=1. Other levels remove NO_COLOR and
+// CLICOLOR, and set CLICOLOR_FORCE=1 and FORCE_COLOR to 1, 2 or 3 for basic, 256 or true colors.
+// COLORTERM=truecolor is only set for true colors, otherwise it's removed so that the child doesn't exceed l.
+func SetCommandLevel(cmd *exec.Cmd, l Level) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	cmd.Env = commandEnv(env, l)
+}
+
+// StartCommand sets the environment of cmd like SetCommandLevel and starts it.
+// The returned function waits for the command to exit, like cmd.Wait, and must be called to release its resources.
+func StartCommand(cmd *exec.Cmd, l Level, opts ...CommandOption) (wait func() error, err error) {
+	c := &commandConfig{}
+	for _, opt := range opts {
+		opt(c)
+	}
+	SetCommandLevel(cmd, l)
+	if c.pty {
+		return startPTY(cmd)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return cmd.Wait, nil
+}
+
+// RunCommand starts cmd like StartCommand and waits for it to exit.
+func RunCommand(cmd *exec.Cmd, l Level, opts ...CommandOption) error {
+	wait, err := StartCommand(cmd, l, opts...)
+	if err != nil {
+		return err
+	}
+	return wait()
+}
+
+// commandEnv returns env without the color variables, followed by the ones that enable the colors of l.
+func commandEnv(env []string, l Level) []string {
+	out := make([]string, 0, len(env)+3)
+	for _, kv := range env {
+		if !isColorEnv(kv) {
+			out = append(out, kv)
+		}
+	}
+	switch {
+	case l == LevelNone:
+		return append(out, "NO_COLOR=1")
+	case l.AtLeast(Level16M):
+		return append(out, "FORCE_COLOR=3", "CLICOLOR_FORCE=1", "COLORTERM=truecolor")
+	case l.AtLeast(Level256):
+		return append(out, "FORCE_COLOR=2", "CLICOLOR_FORCE=1")
+	default:
+		return append(out, "FORCE_COLOR=1", "CLICOLOR_FORCE=1")
+	}
+}
+
+func isColorEnv(kv string) bool {
+	for _, key := range colorEnvKeys {
+		if strings.HasPrefix(kv, key+"=") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package termcolor
+
+import (
+	"bytes"
+	"os/exec"
+	"testing"
+)
+
+func TestRunCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	testCases := map[string]struct {
+		opts []CommandOption
+
+		wantedOutput string
+	}{
+		"pipe": {
+			wantedOutput: "pipe 2\n",
+		},
+		"pseudo-terminal": {
+			opts:         []CommandOption{WithPTY()},
+			wantedOutput: "tty 2\nerr\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			// Given
+			if len(tc.opts) > 0 {
+				master, slave := openTestPTY(t)
+				master.Close()
+				slave.Close()
+			}
+			var out bytes.Buffer
+			cmd := exec.Command("sh", "-c", `if [ -t 1 ]; then echo tty $FORCE_COLOR; else echo pipe $FORCE_COLOR; fi; [ -t 2 ] && echo err >&2; true`)
+			cmd.Stdout = &out
+
+			// When
+			err := RunCommand(cmd, Level256, tc.opts...)
+
+			// Then
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if out.String() != tc.wantedOutput {
+				t.Errorf("expected %q, got %q", tc.wantedOutput, out.String())
+			}
+		})
+	}
+}
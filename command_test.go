@@ -0,0 +1,76 @@
+package termcolor
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestSetCommandLevel(t *testing.T) {
+	testCases := map[string]struct {
+		env   []string
+		level Level
+
+		wantedEnv []string
+	}{
+		"disables colors": {
+			env:       []string{"HOME=/home/user", "FORCE_COLOR=3", "CLICOLOR_FORCE=1", "COLORTERM=truecolor"},
+			level:     LevelNone,
+			wantedEnv: []string{"HOME=/home/user", "NO_COLOR=1"},
+		},
+		"forces basic colors": {
+			env:       []string{"NO_COLOR=1", "CLICOLOR=0", "TERM=xterm"},
+			level:     LevelBasic,
+			wantedEnv: []string{"TERM=xterm", "FORCE_COLOR=1", "CLICOLOR_FORCE=1"},
+		},
+		"forces basic colors for 8 colors": {
+			level:     Level8,
+			wantedEnv: []string{"FORCE_COLOR=1", "CLICOLOR_FORCE=1"},
+		},
+		"forces 256 colors without COLORTERM": {
+			env:       []string{"COLORTERM=truecolor"},
+			level:     Level256,
+			wantedEnv: []string{"FORCE_COLOR=2", "CLICOLOR_FORCE=1"},
+		},
+		"forces true colors": {
+			env:       []string{"FORCE_COLOR=1", "COLORTERM=24bit", "NO_COLOR_DEBUG=1"},
+			level:     Level16M,
+			wantedEnv: []string{"NO_COLOR_DEBUG=1", "FORCE_COLOR=3", "CLICOLOR_FORCE=1", "COLORTERM=truecolor"},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			cmd := exec.Command("true")
+			cmd.Env = append([]string{}, tc.env...)
+
+			// When
+			SetCommandLevel(cmd, tc.level)
+
+			// Then
+			if !reflect.DeepEqual(cmd.Env, tc.wantedEnv) {
+				t.Errorf("expected %q, got %q", tc.wantedEnv, cmd.Env)
+			}
+		})
+	}
+}
+
+func TestSetCommandLevel_InheritsEnvironment(t *testing.T) {
+	// Given
+	cmd := exec.Command("true")
+
+	// When
+	SetCommandLevel(cmd, Level256)
+
+	// Then
+	if len(cmd.Env) < 2 {
+		t.Fatalf("expected the environment of the process followed by the color variables, got %q", cmd.Env)
+	}
+	if got := cmd.Env[len(cmd.Env)-2:]; !reflect.DeepEqual(got, []string{"FORCE_COLOR=2", "CLICOLOR_FORCE=1"}) {
+		t.Errorf("expected the color variables last, got %q", got)
+	}
+}
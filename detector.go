@@ -0,0 +1,410 @@
+package termcolor
+
+import (
+	"os"
+	"regexp"
+	"strconv"
+)
+
+// Detector determines the color level of a file descriptor.
+// Unlike SupportLevel, a Detector only reads the environment variables, command line arguments and terminal state
+// it was configured with, so it can evaluate color support for processes other than the current one.
+type Detector struct {
+	lookupEnv    func(key string) (string, bool)
+	args         []string
+	isTerminal   func(fd uintptr) bool
+	terminfoDirs []string
+	runCommand   CommandRunner
+	policy       ColorPolicy
+	mode         *ColorMode
+	sniffArgs    bool
+	remote       bool
+	commands     *commandCache
+}
+
+// DetectorOption configures a Detector.
+type DetectorOption func(d *Detector)
+
+// WithLookupEnv sets the function used to retrieve environment variables.
+// The function should behave like os.LookupEnv.
+func WithLookupEnv(lookupEnv func(key string) (string, bool)) DetectorOption {
+	return func(d *Detector) {
+		d.lookupEnv = lookupEnv
+	}
+}
+
+// WithEnv sets the environment variables of the detector from a map.
+func WithEnv(env map[string]string) DetectorOption {
+	return WithLookupEnv(func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	})
+}
+
+// WithArgs sets the command line arguments scanned for color flags.
+// Similar to os.Args, the first element is the program name.
+func WithArgs(args []string) DetectorOption {
+	return func(d *Detector) {
+		d.args = args
+	}
+}
+
+// WithTerminalChecker sets the function that reports whether a file descriptor is a terminal.
+func WithTerminalChecker(isTerminal func(fd uintptr) bool) DetectorOption {
+	return func(d *Detector) {
+		d.isTerminal = isTerminal
+	}
+}
+
+// WithTerminfoDirs sets the system directories searched for terminfo entries.
+// The directories named by the TERMINFO, HOME and TERMINFO_DIRS environment variables are always searched first.
+func WithTerminfoDirs(dirs ...string) DetectorOption {
+	return func(d *Detector) {
+		d.terminfoDirs = dirs
+	}
+}
+
+// NewDetector returns a Detector configured with opts.
+// By default, the detector reads the environment and arguments of the current process, checks file descriptors
+// with isatty, and runs tmux to read its options when the process runs inside tmux. The options are read once per
+// process, see ResetCache.
+func NewDetector(opts ...DetectorOption) *Detector {
+	d := &Detector{
+		lookupEnv:    os.LookupEnv,
+		args:         args,
+		isTerminal:   isTerminal,
+		terminfoDirs: terminfoSystemDirs,
+		runCommand:   runCommand,
+		commands:     defaultCommandCache,
+		policy:       DefaultPolicy,
+		sniffArgs:    true,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Level returns the color level that's supported by the file descriptor.
+// The level is one of LevelNone, LevelBasic, Level256 or Level16M: terminals with 8 or 88 colors are reported as
+// LevelBasic. Use Colors to distinguish them.
+func (d *Detector) Level(f FileDescriptor) Level {
+	e := detection{d: d}
+	return e.detect(f).standard()
+}
+
+// Colors returns the number of colors that the file descriptor supports, such as 8 for the Linux console or 88 for
+// rxvt-88color, or 0 if it doesn't support colors. ColorsLevel returns the level to render them with.
+func (d *Detector) Colors(f FileDescriptor) int {
+	e := detection{d: d}
+	return e.detect(f).Colors()
+}
+
+// Explain returns the color level that's supported by the file descriptor along with the rules that were consulted
+// to reach it.
+func (d *Detector) Explain(f FileDescriptor) Decision {
+	e := detection{d: d, trace: true}
+	l := e.detect(f)
+	return Decision{
+		Level:  l.standard(),
+		Colors: l.Colors(),
+		Steps:  e.steps,
+	}
+}
+
+func (e *detection) detect(f FileDescriptor) Level {
+	// Flags take priority over anything else.
+	e.begin("hasDisabledFlag")
+	if e.hasDisabledFlag() {
+		return e.decide(LevelNone)
+	}
+	e.begin("has16MFlag")
+	if e.has16MFlag() {
+		return e.decide(Level16M)
+	}
+	e.begin("has256Flag")
+	if e.has256Flag() {
+		return e.decide(Level256)
+	}
+
+	e.begin("lookupColorEnv")
+	forced, isForced := e.lookupColorEnv()
+	if isForced && forced == LevelNone && !e.hasEnabledFlag() {
+		// Flags override the environment, as required by no-color.org.
+		return e.decide(LevelNone)
+	}
+
+	e.begin("isTerminal")
+	if !e.isTerminal(f.Fd()) {
+		// If the user forces colors proceed even though it's not a terminal.
+		if forced == LevelNone && !e.hasAlwaysMode() {
+			return e.decide(LevelNone)
+		}
+	}
+
+	e.begin("minLevel")
+	min := e.minLevel(forced)
+	// Retrieve color from environment variables. The rules below never return less than the forced level.
+	e.begin("isDumbTerminal")
+	if e.isDumbTerminal() {
+		return e.decide(min)
+	}
+	e.begin("lookupWindows")
+	if l, isWindows := e.lookupWindows(); isWindows {
+		return e.decide(atLeast(l, min))
+	}
+	e.begin("lookupCI")
+	if l, isCI := e.lookupCI(min); isCI {
+		return e.decide(l)
+	}
+	e.begin("lookupMultiplexer")
+	m, l := e.lookupMultiplexer()
+	if l != LevelNone {
+		return e.decide(atLeast(l, min))
+	}
+	// Multiplexers are terminals of their own: the variables describing the outer terminal don't apply.
+	if m == NoMultiplexer {
+		e.begin("isTrueColorTerminal")
+		if e.isTrueColorTerminal() {
+			return e.decide(atLeast(Level16M, min))
+		}
+		e.begin("lookupTerminal")
+		if l, isKnown := e.lookupTerminal(); isKnown {
+			return e.decide(atLeast(l, min))
+		}
+	}
+	e.begin("is8Terminal")
+	if e.is8Terminal() {
+		// The entry of the Linux console has 8 colors like xterm's, but the console doesn't render the bright ones.
+		return e.decide(atLeast(Level8, min))
+	}
+	e.begin("lookupTerminfo")
+	if l, hasEntry := e.lookupTerminfo(); hasEntry {
+		if l != LevelNone {
+			return e.decide(atLeast(l, min))
+		}
+		// The entries of DEC's terminals have no colors, but the emulators that use their names render them.
+		e.begin("isVTTerminal")
+		if e.isVTTerminal() {
+			return e.decide(atLeast(LevelBasic, min))
+		}
+	} else {
+		// Guess the level from the name of the terminal only if there is no terminfo entry.
+		e.begin("is256Terminal")
+		if e.is256Terminal() {
+			return e.decide(atLeast(Level256, min))
+		}
+		e.begin("is88Terminal")
+		if e.is88Terminal() {
+			return e.decide(atLeast(Level88, min))
+		}
+		e.begin("isBasicTerminal")
+		if e.isBasicTerminal() {
+			return e.decide(atLeast(LevelBasic, min))
+		}
+	}
+	e.begin("hasColorTerm")
+	if e.hasColorTerm() {
+		return e.decide(atLeast(LevelBasic, min))
+	}
+	e.begin("default")
+	return e.decide(min)
+}
+
+func (e *detection) hasDisabledFlag() bool {
+	if m, ok := e.colorMode(); ok {
+		return m == ColorModeNever
+	}
+	if e.hasFlag("no-color") {
+		return true
+	}
+	if e.hasFlag("no-colors") {
+		return true
+	}
+	if e.hasFlag("color=false") {
+		return true
+	}
+	return e.hasFlag("color=never")
+}
+
+func (e *detection) has16MFlag() bool {
+	if m, ok := e.colorMode(); ok {
+		return m == ColorMode16M
+	}
+	if e.hasFlag("color=16m") {
+		return true
+	}
+	if e.hasFlag("color=full") {
+		return true
+	}
+	return e.hasFlag("color=truecolor")
+}
+
+func (e *detection) has256Flag() bool {
+	if m, ok := e.colorMode(); ok {
+		return m == ColorMode256
+	}
+	return e.hasFlag("color=256")
+}
+
+// hasAlwaysMode returns true if colors are enabled by ColorModeAlways, which applies even if the file descriptor
+// isn't a terminal, unlike the --color flag found in the arguments.
+func (e *detection) hasAlwaysMode() bool {
+	m, ok := e.colorMode()
+	return ok && m == ColorModeAlways
+}
+
+// atLeast returns l, or min if it has more colors than l.
+func atLeast(l, min Level) Level {
+	if l.AtLeast(min) {
+		return l
+	}
+	return min
+}
+
+// minLevel returns the level forced by the environment, or LevelBasic if colors are enabled by a flag.
+func (e *detection) minLevel(forced Level) Level {
+	if forced != LevelNone {
+		return forced
+	}
+	if e.hasEnabledFlag() {
+		return LevelBasic
+	}
+	return LevelNone
+}
+
+func (e *detection) hasEnabledFlag() bool {
+	if m, ok := e.colorMode(); ok {
+		return m == ColorModeAlways
+	}
+	if e.hasFlag("color") {
+		return true
+	}
+	if e.hasFlag("colors") {
+		return true
+	}
+	if e.hasFlag("color=true") {
+		return true
+	}
+	return e.hasFlag("color=always")
+}
+
+func forceColorValue(fc string) Level {
+	if fc == "true" {
+		return LevelBasic
+	}
+	if fc == "false" {
+		return LevelNone
+	}
+	num, err := strconv.Atoi(fc)
+	if err != nil {
+		// If not a number then return basic colors.
+		return LevelBasic
+	}
+	// The values follow chalk: 1 for basic colors, 2 for 256 colors and 3 for true colors.
+	switch num {
+	case 0:
+		return LevelNone
+	case 2:
+		return Level256
+	case 3:
+		return Level16M
+	default:
+		// If the number is out of bounds default to basic.
+		return LevelBasic
+	}
+}
+
+func (e *detection) isDumbTerminal() bool {
+	return e.getenv("TERM") == "dumb"
+}
+
+func (e *detection) isTrueColorTerminal() bool {
+	return e.getenv("COLORTERM") == "truecolor"
+}
+
+// colored256Screen matches terminals containing "-256" or "-256color".
+var colored256Screen = regexp.MustCompile(`-256(color)`)
+
+func (e *detection) is256Terminal() bool {
+	return colored256Screen.MatchString(e.getenv("TERM"))
+}
+
+// colored88Screen matches terminals containing "-88color", such as "rxvt-88color".
+var colored88Screen = regexp.MustCompile(`-88color`)
+
+func (e *detection) is88Terminal() bool {
+	return colored88Screen.MatchString(e.getenv("TERM"))
+}
+
+// is8Terminal returns true for the Linux console, which renders the bright colors with the normal ones.
+func (e *detection) is8Terminal() bool {
+	return e.getenv("TERM") == "linux"
+}
+
+// coloredScreen matches other well known basic colored terminals.
+var coloredScreen = regexp.MustCompile(`^screen|^xterm|^vt100|^vt220|^rxvt|color|ansi|cygwin|linux`)
+
+func (e *detection) isBasicTerminal() bool {
+	return coloredScreen.MatchString(e.getenv("TERM"))
+}
+
+// vtScreen matches the DEC terminals that coloredScreen matches.
+var vtScreen = regexp.MustCompile(`^vt100|^vt220`)
+
+func (e *detection) isVTTerminal() bool {
+	return vtScreen.MatchString(e.getenv("TERM"))
+}
+
+func (e *detection) hasColorTerm() bool {
+	return e.hasEnv("COLORTERM")
+}
+
+// lookupTerminfo returns the color level advertised by the terminfo entry of the terminal.
+// If there is no entry for the terminal, then returns false.
+func (e *detection) lookupTerminfo() (Level, bool) {
+	ti, ok := e.terminfo()
+	if !ok {
+		return LevelNone, false
+	}
+	return ti.level(), true
+}
+
+// terminfo loads the terminfo entry of the terminal named by TERM.
+// If there is no entry or it can't be read, then returns false.
+func (e *detection) terminfo() (*terminfo, bool) {
+	term := e.getenv("TERM")
+	if term == "" {
+		return nil, false
+	}
+	getenv := e.getenv
+	if e.d.remote {
+		// The directories named by a remote environment don't exist on this machine, and could point anywhere.
+		getenv = func(string) string { return "" }
+	}
+	path, err := findTerminfo(term, terminfoDirs(getenv, e.d.terminfoDirs))
+	if err != nil {
+		e.record(Input{
+			Source: SourceTerminfo,
+			Name:   term,
+		})
+		return nil, false
+	}
+	ti, err := loadTerminfo(path)
+	if err != nil {
+		// Treat unreadable entries as missing so that the name of the terminal is used instead.
+		e.record(Input{
+			Source: SourceTerminfo,
+			Name:   path,
+			Value:  err.Error(),
+		})
+		return nil, false
+	}
+	e.record(Input{
+		Source:  SourceTerminfo,
+		Name:    path,
+		Value:   ti.colorCaps(),
+		Present: true,
+	})
+	return ti, true
+}
@@ -0,0 +1,314 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetector_Level(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		envs       map[string]string
+		isTerminal bool
+
+		wantedLevel Level
+	}{
+		"flags take priority over the environment": {
+			args: []string{"cli", "--no-color"},
+			envs: map[string]string{
+				"FORCE_COLOR": "3",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"flags after the terminator are ignored": {
+			args:        []string{"cli", "--", "--color=16m"},
+			wantedLevel: LevelNone,
+		},
+		"with a fd that's not a terminal": {
+			envs: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			wantedLevel: LevelNone,
+		},
+		"with FORCE_COLOR when not a terminal": {
+			envs: map[string]string{
+				"FORCE_COLOR": "2",
+			},
+			wantedLevel: Level256,
+		},
+		"with a dumb terminal": {
+			envs: map[string]string{
+				"TERM": "dumb",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"with iTerm 3": {
+			envs: map[string]string{
+				"TERM_PROGRAM":         "iTerm.app",
+				"TERM_PROGRAM_VERSION": "3.3.7",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"with xterm-256color": {
+			envs: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"with a terminfo entry advertising direct colors": {
+			envs: map[string]string{
+				"TERM":     "xterm-direct",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"with a terminfo entry from TERMINFO_DIRS": {
+			envs: map[string]string{
+				"TERM":          "termcolor-256",
+				"TERMINFO_DIRS": "testdata/missing:testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"with xterm's terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "xterm",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with screen's terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "screen",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"FORCE_COLOR raises a terminal program": {
+			envs: map[string]string{
+				"TERM_PROGRAM": "Apple_Terminal",
+				"FORCE_COLOR":  "3",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"FORCE_COLOR raises the Linux console": {
+			envs: map[string]string{
+				"TERM":        "linux",
+				"FORCE_COLOR": "3",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"FORCE_COLOR raises a terminfo entry": {
+			envs: map[string]string{
+				"TERM":        "xterm",
+				"TERMINFO":    "testdata/terminfo",
+				"FORCE_COLOR": "2",
+			},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"FORCE_COLOR raises a terminal name": {
+			envs: map[string]string{
+				"TERM":        "rxvt-88color",
+				"FORCE_COLOR": "3",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"FORCE_COLOR doesn't lower a terminfo entry": {
+			envs: map[string]string{
+				"TERM":        "termcolor-direct",
+				"TERMINFO":    "testdata/terminfo",
+				"FORCE_COLOR": "1",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+		},
+		"with the Linux console's terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "linux",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with the Linux console without terminfo": {
+			envs: map[string]string{
+				"TERM": "linux",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with rxvt-88color": {
+			envs: map[string]string{
+				"TERM": "rxvt-88color",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with vt100's terminfo entry without colors": {
+			envs: map[string]string{
+				"TERM":     "vt100",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with vt220's terminfo entry without colors": {
+			envs: map[string]string{
+				"TERM":     "vt220",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with a terminfo entry without colors": {
+			envs: map[string]string{
+				"TERM":     "termcolor-mono",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"with a terminfo entry without colors and COLORTERM": {
+			envs: map[string]string{
+				"TERM":      "termcolor-mono",
+				"TERMINFO":  "testdata/terminfo",
+				"COLORTERM": "1",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelBasic,
+		},
+		"with an empty environment": {
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(tc.args),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+			)
+
+			// When
+			l := d.Level(os.Stdout)
+
+			// Then
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestDetector_Colors(t *testing.T) {
+	testCases := map[string]struct {
+		envs       map[string]string
+		isTerminal bool
+
+		wantedColors int
+	}{
+		"with the Linux console": {
+			envs: map[string]string{
+				"TERM": "linux",
+			},
+			isTerminal:   true,
+			wantedColors: 8,
+		},
+		"with the Linux console's terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "linux",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:   true,
+			wantedColors: 8,
+		},
+		"with xterm's terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "xterm",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:   true,
+			wantedColors: 16,
+		},
+		"with rxvt-88color": {
+			envs: map[string]string{
+				"TERM": "rxvt-88color",
+			},
+			isTerminal:   true,
+			wantedColors: 88,
+		},
+		"with true colors": {
+			envs: map[string]string{
+				"COLORTERM": "truecolor",
+			},
+			isTerminal:   true,
+			wantedColors: 1 << 24,
+		},
+		"with a fd that's not a terminal": {
+			envs: map[string]string{
+				"TERM": "linux",
+			},
+			wantedColors: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+			)
+
+			// When
+			n := d.Colors(os.Stdout)
+			decision := d.Explain(os.Stdout)
+
+			// Then
+			if n != tc.wantedColors {
+				t.Errorf("expected %d colors, got %d", tc.wantedColors, n)
+			}
+			if decision.Colors != n {
+				t.Errorf("expected the decision to report %d colors, got %d", n, decision.Colors)
+			}
+			if decision.Level != ColorsLevel(n).standard() {
+				t.Errorf("expected the decision's level to match %d colors, got %v", n, decision.Level)
+			}
+		})
+	}
+}
+
+func TestColorsLevel(t *testing.T) {
+	for n, wanted := range map[int]Level{0: LevelNone, 7: LevelNone, 8: Level8, 16: LevelBasic, 100: Level88, 256: Level256, 1 << 24: Level16M} {
+		if l := ColorsLevel(n); l != wanted {
+			t.Errorf("expected %d colors to be rendered with %v, got %v", n, wanted, l)
+		}
+	}
+}
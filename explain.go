@@ -0,0 +1,278 @@
+package termcolor
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Source is the kind of input that a detection rule looks at.
+type Source int
+
+// Sources of inputs that can be consulted by detection rules.
+const (
+	// SourceFlag represents a command line flag.
+	SourceFlag Source = iota + 1
+	// SourceEnv represents an environment variable.
+	SourceEnv
+	// SourceTTY represents whether the file descriptor is a terminal.
+	SourceTTY
+	// SourceOS represents the operating system the process is running on.
+	SourceOS
+	// SourceTerminfo represents a compiled terminfo entry.
+	SourceTerminfo
+	// SourceCommand represents the output of a command, such as tmux.
+	SourceCommand
+)
+
+// String returns the name of the source.
+func (s Source) String() string {
+	switch s {
+	case SourceFlag:
+		return "flag"
+	case SourceEnv:
+		return "env"
+	case SourceTTY:
+		return "tty"
+	case SourceOS:
+		return "os"
+	case SourceTerminfo:
+		return "terminfo"
+	case SourceCommand:
+		return "command"
+	default:
+		return "unknown"
+	}
+}
+
+// Input is a value that was looked at by a detection rule.
+type Input struct {
+	Source Source
+	// Name identifies the input, such as "--no-color" for a flag or "TERM" for an environment variable.
+	// For terminfo entries, it's the path of the entry or the terminal name if no entry was found.
+	Name string
+	// Value is the value of the input. It's empty for flags and TTY states.
+	// For terminfo entries, it's the color capabilities of the entry or the reason it couldn't be read.
+	Value string
+	// Present is true if the flag or environment variable is set, if the file descriptor is a terminal,
+	// or if the terminfo entry was loaded.
+	Present bool
+}
+
+// String returns the input in a human readable format.
+func (in Input) String() string {
+	switch in.Source {
+	case SourceEnv:
+		if !in.Present {
+			return fmt.Sprintf("env %s is unset", in.Name)
+		}
+		return fmt.Sprintf("env %s=%q", in.Name, in.Value)
+	case SourceFlag:
+		if !in.Present {
+			return fmt.Sprintf("flag %s is absent", in.Name)
+		}
+		return fmt.Sprintf("flag %s is present", in.Name)
+	case SourceTTY:
+		if !in.Present {
+			return fmt.Sprintf("%s is not a terminal", in.Name)
+		}
+		return fmt.Sprintf("%s is a terminal", in.Name)
+	case SourceTerminfo:
+		if !in.Present {
+			if in.Value != "" {
+				return fmt.Sprintf("terminfo %s is invalid: %s", in.Name, in.Value)
+			}
+			return fmt.Sprintf("terminfo entry for %q not found", in.Name)
+		}
+		return fmt.Sprintf("terminfo %s has %s", in.Name, in.Value)
+	case SourceCommand:
+		if !in.Present {
+			return fmt.Sprintf("command %q failed: %s", in.Name, in.Value)
+		}
+		return fmt.Sprintf("command %q printed %q", in.Name, in.Value)
+	default:
+		return fmt.Sprintf("%s %s", in.Source, in.Name)
+	}
+}
+
+// Step is a rule that was consulted while detecting the color level.
+type Step struct {
+	// Rule is the name of the rule, such as "hasDisabledFlag" or "isDumbTerminal".
+	Rule string
+	// Inputs are the flags, environment variables and TTY states that the rule looked at, in order.
+	Inputs []Input
+	// Decided is true if the rule determined the final level.
+	Decided bool
+	// Level is the level chosen by the rule. It's only meaningful if Decided is true.
+	Level Level
+}
+
+// Decision is the color level of a file descriptor along with the rules that were consulted to reach it.
+type Decision struct {
+	// Level is the level returned by Detector.Level.
+	Level Level
+	// Colors is the number of colors returned by Detector.Colors.
+	Colors int
+	// Steps are the rules that were consulted in order. The last step is the one that decided the level.
+	Steps []Step
+}
+
+// Decider returns the rule that decided the level.
+// If no rule decided the level, then returns false.
+func (d Decision) Decider() (Step, bool) {
+	if len(d.Steps) == 0 {
+		return Step{}, false
+	}
+	last := d.Steps[len(d.Steps)-1]
+	return last, last.Decided
+}
+
+// String returns the decision in a human readable format with one rule per line.
+func (d Decision) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "level %v", d.Level)
+	if step, ok := d.Decider(); ok {
+		fmt.Fprintf(&b, " decided by %s", step.Rule)
+	}
+	for _, step := range d.Steps {
+		b.WriteString("\n  ")
+		b.WriteString(step.Rule)
+		if step.Decided {
+			fmt.Fprintf(&b, " -> level %v", step.Level)
+		}
+		for i, in := range step.Inputs {
+			if i == 0 {
+				b.WriteString(": ")
+			} else {
+				b.WriteString(", ")
+			}
+			b.WriteString(in.String())
+		}
+	}
+	return b.String()
+}
+
+// Explain returns the color level that's supported by the file descriptor along with the rules that were consulted
+// to reach it.
+func Explain(f FileDescriptor) Decision {
+	return NewDetector().Explain(f)
+}
+
+// detection holds the state of a single color level detection.
+// If trace is true, every rule and the inputs it looked at are recorded in steps.
+type detection struct {
+	d     *Detector
+	trace bool
+	steps []Step
+}
+
+// begin starts recording a new rule.
+func (e *detection) begin(rule string) {
+	if !e.trace {
+		return
+	}
+	e.steps = append(e.steps, Step{Rule: rule})
+}
+
+// decide marks the current rule as the one that determined the level.
+func (e *detection) decide(l Level) Level {
+	if !e.trace {
+		return l
+	}
+	step := &e.steps[len(e.steps)-1]
+	step.Decided = true
+	step.Level = l.standard()
+	return l
+}
+
+func (e *detection) record(in Input) {
+	if !e.trace {
+		return
+	}
+	step := &e.steps[len(e.steps)-1]
+	step.Inputs = append(step.Inputs, in)
+}
+
+func (e *detection) lookupEnv(key string) (string, bool) {
+	v, ok := e.d.lookupEnv(key)
+	e.record(Input{
+		Source:  SourceEnv,
+		Name:    key,
+		Value:   v,
+		Present: ok,
+	})
+	return v, ok
+}
+
+func (e *detection) getenv(key string) string {
+	v, _ := e.lookupEnv(key)
+	return v
+}
+
+func (e *detection) hasEnv(key string) bool {
+	_, ok := e.lookupEnv(key)
+	return ok
+}
+
+// onceLookupEnv returns a function that looks up each environment variable once.
+// Rules that go through a registry share variables such as CI or TERM_PROGRAM, so it keeps them from being
+// recorded several times.
+func (e *detection) onceLookupEnv() func(key string) (string, bool) {
+	type result struct {
+		v  string
+		ok bool
+	}
+	seen := make(map[string]result)
+	return func(key string) (string, bool) {
+		r, cached := seen[key]
+		if !cached {
+			r.v, r.ok = e.lookupEnv(key)
+			seen[key] = r
+		}
+		return r.v, r.ok
+	}
+}
+
+func (e *detection) hasFlag(flag string) bool {
+	if !e.d.sniffArgs {
+		return false
+	}
+	ok := hasFlag(e.d.args, flag)
+	e.record(Input{
+		Source:  SourceFlag,
+		Name:    flagName(flag),
+		Present: ok,
+	})
+	return ok
+}
+
+func (e *detection) isTerminal(fd uintptr) bool {
+	if fd == noFd {
+		e.record(Input{
+			Source: SourceTTY,
+			Name:   "writer without file descriptor",
+		})
+		return false
+	}
+	ok := e.d.isTerminal(fd)
+	e.record(Input{
+		Source:  SourceTTY,
+		Name:    fmt.Sprintf("fd %d", fd),
+		Present: ok,
+	})
+	return ok
+}
+
+func (e *detection) lookupWindows() (Level, bool) {
+	if e.d.remote {
+		// The console of this machine isn't the one of the remote session.
+		return LevelNone, false
+	}
+	l, ok := lookupWindows()
+	e.record(Input{
+		Source:  SourceOS,
+		Name:    runtime.GOOS,
+		Present: ok,
+	})
+	return l, ok
+}
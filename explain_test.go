@@ -0,0 +1,189 @@
+package termcolor
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestDetector_Explain(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		envs       map[string]string
+		isTerminal bool
+
+		wantedLevel   Level
+		wantedRules   []string
+		wantedDecider Step
+	}{
+		"decided by a disabled flag": {
+			args:        []string{"cli", "--no-colors"},
+			wantedLevel: LevelNone,
+			wantedRules: []string{"hasDisabledFlag"},
+			wantedDecider: Step{
+				Rule: "hasDisabledFlag",
+				Inputs: []Input{
+					{Source: SourceFlag, Name: "--no-color"},
+					{Source: SourceFlag, Name: "--no-colors", Present: true},
+				},
+				Decided: true,
+				Level:   LevelNone,
+			},
+		},
+		"decided by the tty state": {
+			args:        []string{"cli"},
+			wantedLevel: LevelNone,
+			wantedRules: []string{"hasDisabledFlag", "has16MFlag", "has256Flag", "lookupColorEnv", "isTerminal"},
+			wantedDecider: Step{
+				Rule: "isTerminal",
+				Inputs: []Input{
+					{Source: SourceTTY, Name: "fd 1"},
+				},
+				Decided: true,
+				Level:   LevelNone,
+			},
+		},
+		"decided by NO_COLOR": {
+			envs: map[string]string{
+				"NO_COLOR": "1",
+				"TERM":     "xterm-256color",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+			wantedRules: []string{"hasDisabledFlag", "has16MFlag", "has256Flag", "lookupColorEnv"},
+			wantedDecider: Step{
+				Rule: "lookupColorEnv",
+				Inputs: []Input{
+					{Source: SourceEnv, Name: "FORCE_COLOR"},
+					{Source: SourceEnv, Name: "NO_COLOR", Value: "1", Present: true},
+					{Source: SourceFlag, Name: "--color"},
+					{Source: SourceFlag, Name: "--colors"},
+					{Source: SourceFlag, Name: "--color=true"},
+					{Source: SourceFlag, Name: "--color=always"},
+				},
+				Decided: true,
+				Level:   LevelNone,
+			},
+		},
+		"decided by a dumb terminal": {
+			envs: map[string]string{
+				"TERM": "dumb",
+			},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+			wantedRules: []string{"hasDisabledFlag", "has16MFlag", "has256Flag", "lookupColorEnv", "isTerminal", "minLevel", "isDumbTerminal"},
+			wantedDecider: Step{
+				Rule: "isDumbTerminal",
+				Inputs: []Input{
+					{Source: SourceEnv, Name: "TERM", Value: "dumb", Present: true},
+				},
+				Decided: true,
+				Level:   LevelNone,
+			},
+		},
+		"decided by the terminfo entry": {
+			envs: map[string]string{
+				"TERM":     "termcolor-tc",
+				"TERMINFO": "testdata/terminfo",
+			},
+			isTerminal:  true,
+			wantedLevel: Level16M,
+			wantedRules: []string{"hasDisabledFlag", "has16MFlag", "has256Flag", "lookupColorEnv", "isTerminal", "minLevel",
+				"isDumbTerminal", "lookupWindows", "lookupCI", "lookupMultiplexer", "isTrueColorTerminal", "lookupTerminal", "is8Terminal",
+				"lookupTerminfo"},
+			wantedDecider: Step{
+				Rule: "lookupTerminfo",
+				Inputs: []Input{
+					{Source: SourceEnv, Name: "TERM", Value: "termcolor-tc", Present: true},
+					{Source: SourceEnv, Name: "TERMINFO", Value: "testdata/terminfo", Present: true},
+					{Source: SourceEnv, Name: "HOME"},
+					{Source: SourceEnv, Name: "TERMINFO_DIRS"},
+					{Source: SourceTerminfo, Name: "testdata/terminfo/t/termcolor-tc", Value: "colors#256 Tc", Present: true},
+				},
+				Decided: true,
+				Level:   Level16M,
+			},
+		},
+		"decided by the TERM regex": {
+			envs: map[string]string{
+				"TERM": "xterm-256color",
+			},
+			isTerminal:  true,
+			wantedLevel: Level256,
+			wantedRules: []string{"hasDisabledFlag", "has16MFlag", "has256Flag", "lookupColorEnv", "isTerminal", "minLevel",
+				"isDumbTerminal", "lookupWindows", "lookupCI", "lookupMultiplexer", "isTrueColorTerminal", "lookupTerminal", "is8Terminal",
+				"lookupTerminfo",
+				"is256Terminal"},
+			wantedDecider: Step{
+				Rule: "is256Terminal",
+				Inputs: []Input{
+					{Source: SourceEnv, Name: "TERM", Value: "xterm-256color", Present: true},
+				},
+				Decided: true,
+				Level:   Level256,
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(tc.args),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+			)
+
+			// When
+			decision := d.Explain(os.Stdout)
+
+			// Then
+			if decision.Level != tc.wantedLevel {
+				t.Errorf("expected level %v, got %v", tc.wantedLevel, decision.Level)
+			}
+			var rules []string
+			for _, step := range decision.Steps {
+				rules = append(rules, step.Rule)
+			}
+			if !reflect.DeepEqual(rules, tc.wantedRules) {
+				t.Errorf("expected rules %v, got %v", tc.wantedRules, rules)
+			}
+			decider, ok := decision.Decider()
+			if !ok {
+				t.Fatalf("expected a rule to decide the level")
+			}
+			if !reflect.DeepEqual(decider, tc.wantedDecider) {
+				t.Errorf("expected decider %+v, got %+v", tc.wantedDecider, decider)
+			}
+			if l := d.Level(os.Stdout); l != decision.Level {
+				t.Errorf("expected Level to match Explain, got %v and %v", l, decision.Level)
+			}
+		})
+	}
+}
+
+func TestDecision_String(t *testing.T) {
+	// Given
+	d := NewDetector(
+		WithArgs([]string{"cli", "--color=256"}),
+		WithEnv(nil),
+	)
+
+	// When
+	s := d.Explain(os.Stdout).String()
+
+	// Then
+	wanted := `level 256 decided by has256Flag
+  hasDisabledFlag: flag --no-color is absent, flag --no-colors is absent, flag --color=false is absent, flag --color=never is absent
+  has16MFlag: flag --color=16m is absent, flag --color=full is absent, flag --color=truecolor is absent
+  has256Flag -> level 256: flag --color=256 is present`
+	if s != wanted {
+		t.Errorf("expected:\n%s\ngot:\n%s", wanted, s)
+	}
+}
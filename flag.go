@@ -9,18 +9,8 @@ import (
 var args = os.Args
 
 // See https://github.com/sindresorhus/has-flag/blob/ecd4cb75870f5d49eef1e0faee328b2019960de3/index.js#L1-L8
-func hasFlag(flag string) bool {
-	// Prefix the flag with the necessary dashes.
-	var prefix string
-	if !strings.HasPrefix(flag, "-") {
-		if len(flag) == 1 {
-			// Short flag.
-			prefix = "-"
-		} else {
-			prefix = "--"
-		}
-	}
-	pos := indexOf(args, prefix + flag)
+func hasFlag(args []string, flag string) bool {
+	pos := indexOf(args, flagName(flag))
 	if pos == -1 {
 		return false
 	}
@@ -33,6 +23,18 @@ func hasFlag(flag string) bool {
 	return pos < terminatorPos
 }
 
+// flagName prefixes the flag with the necessary dashes.
+func flagName(flag string) string {
+	if strings.HasPrefix(flag, "-") {
+		return flag
+	}
+	if len(flag) == 1 {
+		// Short flag.
+		return "-" + flag
+	}
+	return "--" + flag
+}
+
 func indexOf(ss []string, s string) int {
 	for i, el := range ss {
 		if el == s {
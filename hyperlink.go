@@ -0,0 +1,126 @@
+package termcolor
+
+import (
+	"os"
+	"strconv"
+)
+
+// SupportsHyperlinks returns true if the file descriptor renders OSC 8 hyperlinks.
+func SupportsHyperlinks(f FileDescriptor) bool {
+	return NewDetector().SupportsHyperlinks(f)
+}
+
+// Link returns text as a hyperlink to url if the standard output renders OSC 8 hyperlinks.
+// Otherwise, the text is followed by the url in parentheses, or only the url is returned if both are the same.
+func Link(url, text string) string {
+	return NewDetector().Link(os.Stdout, url, text)
+}
+
+// SupportsHyperlinks returns true if the file descriptor renders OSC 8 hyperlinks.
+// The same priorities as Level apply:
+//  1. The --no-hyperlink and --hyperlink flags.
+//  2. The FORCE_HYPERLINK environment variable: any value other than "0" or "false" enables hyperlinks.
+//  3. The terminal state: file descriptors that aren't terminals and dumb terminals don't render them.
+//  4. The log viewer of the continuous integration system.
+//  5. The registered terminals and their versions. Terminals behind a multiplexer are unknown.
+//  6. TERM for terminals that keep their name over ssh, such as "xterm-kitty".
+func (d *Detector) SupportsHyperlinks(f FileDescriptor) bool {
+	e := detection{d: d}
+	return e.hyperlinks(f) == Supported
+}
+
+// Link returns text as a hyperlink to url if the file descriptor renders OSC 8 hyperlinks.
+// Otherwise, the text is followed by the url in parentheses, or only the url is returned if both are the same.
+func (d *Detector) Link(f FileDescriptor, url, text string) string {
+	if d.SupportsHyperlinks(f) {
+		return FormatLink(url, text)
+	}
+	if text == "" || text == url {
+		return url
+	}
+	return text + " (" + url + ")"
+}
+
+// FormatLink returns text as an OSC 8 hyperlink to url, regardless of the terminal.
+func FormatLink(url, text string) string {
+	return "\x1b]8;;" + url + "\x1b\\" + text + "\x1b]8;;\x1b\\"
+}
+
+// hyperlinks returns whether the file descriptor renders hyperlinks, or SupportUnknown if no rule applies.
+func (e *detection) hyperlinks(f FileDescriptor) Support {
+	if e.hasDisabledHyperlinkFlag() {
+		return Unsupported
+	}
+	if e.hasHyperlinkFlag() {
+		return Supported
+	}
+	if v, ok := e.lookupEnv("FORCE_HYPERLINK"); ok {
+		if forceHyperlinkValue(v) {
+			return Supported
+		}
+		return Unsupported
+	}
+	if !e.isTerminal(f.Fd()) || e.isDumbTerminal() {
+		return Unsupported
+	}
+	lookupEnv := e.onceLookupEnv()
+	if p, ok := findCI(lookupEnv); ok {
+		if p.Hyperlinks {
+			return Supported
+		}
+		return Unsupported
+	}
+	if _, isCI := lookupEnv("CI"); isCI {
+		return Unsupported
+	}
+	if e.multiplexer() != NoMultiplexer {
+		return SupportUnknown
+	}
+	if t, ok := findTerminal(lookupEnv); ok {
+		if t.features(lookupEnv)&FeatureHyperlinks != 0 {
+			return Supported
+		}
+		return Unsupported
+	}
+	switch e.getenv("TERM") {
+	case "xterm-kitty", "xterm-ghostty", "alacritty", "wezterm", "foot", "foot-extra":
+		return Supported
+	}
+	return SupportUnknown
+}
+
+func (e *detection) hasDisabledHyperlinkFlag() bool {
+	if e.hasFlag("no-hyperlink") {
+		return true
+	}
+	if e.hasFlag("no-hyperlinks") {
+		return true
+	}
+	if e.hasFlag("hyperlink=false") {
+		return true
+	}
+	return e.hasFlag("hyperlink=never")
+}
+
+func (e *detection) hasHyperlinkFlag() bool {
+	if e.hasFlag("hyperlink") {
+		return true
+	}
+	if e.hasFlag("hyperlinks") {
+		return true
+	}
+	if e.hasFlag("hyperlink=true") {
+		return true
+	}
+	return e.hasFlag("hyperlink=always")
+}
+
+func forceHyperlinkValue(v string) bool {
+	if v == "false" {
+		return false
+	}
+	if num, err := strconv.Atoi(v); err == nil && num == 0 {
+		return false
+	}
+	return true
+}
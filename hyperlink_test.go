@@ -0,0 +1,159 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetector_SupportsHyperlinks(t *testing.T) {
+	testCases := map[string]struct {
+		args       []string
+		envs       map[string]string
+		isTerminal bool
+
+		wanted bool
+	}{
+		"flags take priority over the environment": {
+			args:       []string{"cli", "--no-hyperlink"},
+			envs:       map[string]string{"FORCE_HYPERLINK": "1"},
+			isTerminal: true,
+		},
+		"with the --hyperlink flag when not a terminal": {
+			args:   []string{"cli", "--hyperlink=always"},
+			wanted: true,
+		},
+		"with FORCE_HYPERLINK when not a terminal": {
+			envs:   map[string]string{"FORCE_HYPERLINK": ""},
+			wanted: true,
+		},
+		"with FORCE_HYPERLINK=0": {
+			envs:       map[string]string{"FORCE_HYPERLINK": "0", "KITTY_WINDOW_ID": "1"},
+			isTerminal: true,
+		},
+		"with a fd that's not a terminal": {
+			envs: map[string]string{"KITTY_WINDOW_ID": "1"},
+		},
+		"with a dumb terminal": {
+			envs:       map[string]string{"TERM": "dumb", "KITTY_WINDOW_ID": "1"},
+			isTerminal: true,
+		},
+		"with a CI log viewer that renders hyperlinks": {
+			envs:       map[string]string{"BUILDKITE": "true", "CI": "true"},
+			isTerminal: true,
+			wanted:     true,
+		},
+		"with a CI log viewer": {
+			envs:       map[string]string{"GITHUB_ACTIONS": "true", "CI": "true", "KITTY_WINDOW_ID": "1"},
+			isTerminal: true,
+		},
+		"with iTerm 3.1": {
+			envs:       map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.1.0"},
+			isTerminal: true,
+			wanted:     true,
+		},
+		"with iTerm 3.0": {
+			envs:       map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.0.15"},
+			isTerminal: true,
+		},
+		"with VTE 0.50": {
+			envs:       map[string]string{"VTE_VERSION": "5000"},
+			isTerminal: true,
+			wanted:     true,
+		},
+		"with VTE 0.48": {
+			envs:       map[string]string{"VTE_VERSION": "4803"},
+			isTerminal: true,
+		},
+		"with kitty over ssh": {
+			envs:       map[string]string{"TERM": "xterm-kitty"},
+			isTerminal: true,
+			wanted:     true,
+		},
+		"with kitty inside tmux": {
+			envs:       map[string]string{"KITTY_WINDOW_ID": "1", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			isTerminal: true,
+		},
+		"with an unknown terminal": {
+			envs:       map[string]string{"TERM": "xterm-256color"},
+			isTerminal: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(tc.args),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+				WithCommandRunner(nil),
+			)
+
+			// When
+			got := d.SupportsHyperlinks(os.Stdout)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestDetector_Link(t *testing.T) {
+	testCases := map[string]struct {
+		envs map[string]string
+		url  string
+		text string
+
+		wanted string
+	}{
+		"supported": {
+			envs:   map[string]string{"FORCE_HYPERLINK": "1"},
+			url:    "https://example.com",
+			text:   "example",
+			wanted: "\x1b]8;;https://example.com\x1b\\example\x1b]8;;\x1b\\",
+		},
+		"unsupported": {
+			envs:   map[string]string{"FORCE_HYPERLINK": "0"},
+			url:    "https://example.com",
+			text:   "example",
+			wanted: "example (https://example.com)",
+		},
+		"unsupported with the url as text": {
+			envs:   map[string]string{"FORCE_HYPERLINK": "0"},
+			url:    "https://example.com",
+			text:   "https://example.com",
+			wanted: "https://example.com",
+		},
+		"unsupported without text": {
+			envs:   map[string]string{"FORCE_HYPERLINK": "0"},
+			url:    "https://example.com",
+			wanted: "https://example.com",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(WithArgs(nil), WithEnv(tc.envs))
+
+			// When
+			got := d.Link(os.Stdout, tc.url, tc.text)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, got)
+			}
+		})
+	}
+}
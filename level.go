@@ -0,0 +1,96 @@
+package termcolor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// levelNames maps the names and aliases accepted by ParseLevel to levels.
+var levelNames = map[string]Level{
+	"none":      LevelNone,
+	"0":         LevelNone,
+	"8":         Level8,
+	"basic":     LevelBasic,
+	"16":        LevelBasic,
+	"ansi":      LevelBasic,
+	"88":        Level88,
+	"256":       Level256,
+	"ansi256":   Level256,
+	"truecolor": Level16M,
+	"16m":       Level16M,
+	"24bit":     Level16M,
+	"16777216":  Level16M,
+}
+
+// String returns the name of the level: "none", "8", "16", "88", "256" or "16m".
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case Level8:
+		return "8"
+	case LevelBasic:
+		return "16"
+	case Level88:
+		return "88"
+	case Level256:
+		return "256"
+	case Level16M:
+		return "16m"
+	default:
+		return fmt.Sprintf("Level(%d)", int(l))
+	}
+}
+
+// ParseLevel returns the level named by s, case insensitively.
+// Besides the names returned by String, it accepts the aliases "basic" and "ansi" for 16 colors, "ansi256" for
+// 256 colors, and "truecolor" and "24bit" for true colors. Numbers of colors such as "0" or "16777216" are also
+// accepted.
+func ParseLevel(s string) (Level, error) {
+	l, ok := levelNames[strings.ToLower(strings.TrimSpace(s))]
+	if !ok {
+		return LevelNone, fmt.Errorf("termcolor: invalid level %q", s)
+	}
+	return l, nil
+}
+
+// MarshalText returns the name of the level.
+func (l Level) MarshalText() ([]byte, error) {
+	if l != LevelNone && l.Colors() == 0 {
+		return nil, fmt.Errorf("termcolor: invalid level %d", int(l))
+	}
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText parses text with ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = parsed
+	return nil
+}
+
+// UnmarshalJSON parses a JSON string with ParseLevel, or a JSON number of colors such as 256.
+// Like other unmarshalers, it leaves the level as is for a JSON null.
+func (l *Level) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return l.UnmarshalText([]byte(s))
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return fmt.Errorf("termcolor: invalid level %s", data)
+	}
+	return l.UnmarshalText([]byte(n))
+}
+
+// Set parses s with ParseLevel so that a Level can be registered as a flag.Value.
+func (l *Level) Set(s string) error {
+	return l.UnmarshalText([]byte(s))
+}
@@ -0,0 +1,142 @@
+package termcolor
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := map[string]struct {
+		name string
+
+		wanted    Level
+		wantedErr bool
+	}{
+		"none":           {name: "none", wanted: LevelNone},
+		"basic":          {name: "basic", wanted: LevelBasic},
+		"16":             {name: "16", wanted: LevelBasic},
+		"88":             {name: "88", wanted: Level88},
+		"256":            {name: "256", wanted: Level256},
+		"truecolor":      {name: "truecolor", wanted: Level16M},
+		"16m":            {name: "16M", wanted: Level16M},
+		"24bit":          {name: " 24bit ", wanted: Level16M},
+		"number":         {name: "16777216", wanted: Level16M},
+		"unknown name":   {name: "full", wantedErr: true},
+		"unknown number": {name: "100", wantedErr: true},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			l, err := ParseLevel(tc.name)
+
+			// Then
+			if tc.wantedErr {
+				if err == nil {
+					t.Errorf("expected an error, got level %v", l)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if l != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, l)
+			}
+		})
+	}
+}
+
+func TestLevel_String(t *testing.T) {
+	for _, l := range []Level{LevelNone, Level8, LevelBasic, Level88, Level256, Level16M} {
+		parsed, err := ParseLevel(l.String())
+		if err != nil {
+			t.Errorf("expected %q to parse, got %v", l.String(), err)
+		}
+		if parsed != l {
+			t.Errorf("expected %q to parse as %d, got %d", l.String(), l, parsed)
+		}
+	}
+	if got := Level(42).String(); got != "Level(42)" {
+		t.Errorf("expected Level(42), got %s", got)
+	}
+}
+
+func TestLevel_Values(t *testing.T) {
+	// The values of the levels are published and must not change.
+	for l, wanted := range map[Level]int{LevelNone: 0, LevelBasic: 1, Level256: 2, Level16M: 3, Level8: 4, Level88: 5} {
+		if int(l) != wanted {
+			t.Errorf("expected level %v to be %d, got %d", l, wanted, int(l))
+		}
+	}
+}
+
+func TestLevel_AtLeast(t *testing.T) {
+	ordered := []Level{LevelNone, Level8, LevelBasic, Level88, Level256, Level16M}
+	for i, l := range ordered {
+		for j, other := range ordered {
+			if got, wanted := l.AtLeast(other), i >= j; got != wanted {
+				t.Errorf("expected %v.AtLeast(%v) to be %v, got %v", l, other, wanted, got)
+			}
+		}
+	}
+}
+
+func TestLevel_JSON(t *testing.T) {
+	type config struct {
+		Color Level `json:"color"`
+	}
+	testCases := map[string]struct {
+		input   string
+		initial Level
+
+		wanted    Level
+		wantedErr bool
+	}{
+		"null":           {input: `{"color": null}`, initial: Level256, wanted: Level256},
+		"name":           {input: `{"color": "truecolor"}`, wanted: Level16M},
+		"number":         {input: `{"color": 256}`, wanted: Level256},
+		"number as text": {input: `{"color": "256"}`, wanted: Level256},
+		"invalid name":   {input: `{"color": "lots"}`, wantedErr: true},
+		"invalid type":   {input: `{"color": true}`, wantedErr: true},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			c := config{Color: tc.initial}
+			err := json.Unmarshal([]byte(tc.input), &c)
+
+			// Then
+			if tc.wantedErr {
+				if err == nil {
+					t.Errorf("expected an error, got level %v", c.Color)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if c.Color != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, c.Color)
+			}
+		})
+	}
+
+	out, err := json.Marshal(config{Color: Level256})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if string(out) != `{"color":"256"}` {
+		t.Errorf(`expected {"color":"256"}, got %s`, out)
+	}
+	if _, err := json.Marshal(config{Color: Level(42)}); err == nil {
+		t.Errorf("expected an error for an invalid level")
+	}
+}
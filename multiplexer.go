@@ -0,0 +1,234 @@
+package termcolor
+
+import (
+	"context"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Multiplexer is a terminal multiplexer, such as tmux, that sits between the process and the terminal emulator.
+type Multiplexer int
+
+// Multiplexers that can be detected.
+const (
+	// NoMultiplexer represents a process that talks to the terminal emulator directly.
+	NoMultiplexer Multiplexer = iota
+	// Tmux represents tmux, detected by the TMUX environment variable.
+	Tmux
+	// Screen represents GNU screen, detected by the STY environment variable.
+	Screen
+)
+
+// String returns the name of the multiplexer.
+func (m Multiplexer) String() string {
+	switch m {
+	case NoMultiplexer:
+		return "none"
+	case Tmux:
+		return "tmux"
+	case Screen:
+		return "screen"
+	default:
+		return "unknown"
+	}
+}
+
+// NeedsPassthrough returns true if escape sequences that query the terminal emulator, such as the ones sent by
+// ProbeLevel or QueryBackground, must be wrapped with Wrap to reach it.
+func (m Multiplexer) NeedsPassthrough() bool {
+	return m == Tmux || m == Screen
+}
+
+// screenChunkLen is the length after which GNU screen truncates device control strings.
+const screenChunkLen = 768
+
+// Wrap wraps seq in device control strings that the multiplexer passes through to the terminal emulator as is.
+// tmux only passes them through if its allow-passthrough option is on. GNU screen ends the passthrough at the
+// first string terminator, so sequences sent through screen should be terminated by BEL rather than "ESC \".
+// If the multiplexer doesn't need passthrough, then seq is returned as is.
+func (m Multiplexer) Wrap(seq string) string {
+	switch m {
+	case Tmux:
+		// Escape characters inside the string are doubled.
+		return "\x1bPtmux;" + strings.Replace(seq, "\x1b", "\x1b\x1b", -1) + "\x1b\\"
+	case Screen:
+		var b strings.Builder
+		for len(seq) > 0 {
+			n := len(seq)
+			if n > screenChunkLen {
+				n = screenChunkLen
+			}
+			b.WriteString("\x1bP" + seq[:n] + "\x1b\\")
+			seq = seq[n:]
+		}
+		return b.String()
+	default:
+		return seq
+	}
+}
+
+// CommandRunner runs a command and returns its standard output.
+type CommandRunner func(name string, args ...string) ([]byte, error)
+
+// commandTimeout is how long to wait for commands run by the default CommandRunner.
+const commandTimeout = time.Second
+
+// runCommand is the default CommandRunner.
+func runCommand(name string, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	return exec.CommandContext(ctx, name, args...).Output()
+}
+
+// WithCommandRunner sets the function used to run commands, such as the ones that ask tmux for its options.
+// A nil runner prevents the detector from running commands.
+func WithCommandRunner(run CommandRunner) DetectorOption {
+	return func(d *Detector) {
+		d.runCommand = run
+		d.commands = &commandCache{}
+	}
+}
+
+// commandCache remembers the output of the commands run by detectors, so that a slow or hung tmux server only
+// delays the first detection rather than every one of them.
+type commandCache struct {
+	mu      sync.Mutex
+	outputs map[string]commandOutput
+}
+
+type commandOutput struct {
+	out []byte
+	err error
+}
+
+// defaultCommandCache is shared by the detectors that use the default CommandRunner, such as the ones created by
+// SupportLevel, since they all ask the tmux server of the current process.
+var defaultCommandCache = &commandCache{}
+
+// run returns the output of the command run with run, or the output remembered for key.
+func (c *commandCache) run(run CommandRunner, key string, name string, args ...string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if o, ok := c.outputs[key]; ok {
+		return o.out, o.err
+	}
+	out, err := run(name, args...)
+	if c.outputs == nil {
+		c.outputs = make(map[string]commandOutput)
+	}
+	c.outputs[key] = commandOutput{out: out, err: err}
+	return out, err
+}
+
+// reset forgets the remembered outputs.
+func (c *commandCache) reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.outputs = nil
+}
+
+// Multiplexer returns the terminal multiplexer that the detector's environment runs in.
+func (d *Detector) Multiplexer() Multiplexer {
+	e := detection{d: d}
+	return e.multiplexer()
+}
+
+func (e *detection) multiplexer() Multiplexer {
+	if e.hasEnv("TMUX") {
+		return Tmux
+	}
+	if e.hasEnv("STY") {
+		return Screen
+	}
+	return NoMultiplexer
+}
+
+// lookupMultiplexer returns the multiplexer that the process runs in, and Level16M if it's known to support true
+// colors. tmux supports them if the RGB or Tc capability is set by its terminal-overrides or terminal-features
+// options for the outer terminal. Otherwise, the level is LevelNone and should be derived from TERM.
+func (e *detection) lookupMultiplexer() (Multiplexer, Level) {
+	m := e.multiplexer()
+	if m != Tmux || e.d.runCommand == nil {
+		return m, LevelNone
+	}
+	options, ok := e.run("tmux", "show-options", "-s")
+	if !ok {
+		return m, LevelNone
+	}
+	outer, ok := e.run("tmux", "display-message", "-p", "#{client_termname}")
+	if !ok {
+		outer = ""
+	}
+	if tmuxHasRGB(options, strings.TrimSpace(outer)) {
+		return m, Level16M
+	}
+	return m, LevelNone
+}
+
+// tmuxHasRGB returns true if the terminal-overrides or terminal-features server options give the RGB or Tc
+// capability to the outer terminal. If the outer terminal is unknown, then any pattern matches.
+func tmuxHasRGB(options, outer string) bool {
+	for _, line := range strings.Split(options, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+		if !strings.HasPrefix(fields[0], "terminal-overrides") && !strings.HasPrefix(fields[0], "terminal-features") {
+			continue
+		}
+		// Values have the form "pattern:capability:capability", may be quoted, and may hold several entries
+		// separated by commas.
+		for _, entry := range strings.Split(strings.Trim(fields[1], `"'`), ",") {
+			if entryHasRGB(entry, outer) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func entryHasRGB(entry, outer string) bool {
+	caps := strings.Split(entry, ":")
+	if outer != "" {
+		if ok, _ := path.Match(caps[0], outer); !ok {
+			return false
+		}
+	}
+	for _, c := range caps[1:] {
+		name := strings.SplitN(c, "=", 2)[0]
+		if name == "RGB" || name == "Tc" {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *detection) run(name string, args ...string) (string, bool) {
+	cmdline := strings.Join(append([]string{name}, args...), " ")
+	// The output depends on the tmux server that the environment points to.
+	server, _ := e.d.lookupEnv("TMUX")
+	out, err := e.d.commands.run(e.d.runCommand, server+"\x00"+cmdline, name, args...)
+	in := Input{
+		Source:  SourceCommand,
+		Name:    cmdline,
+		Present: err == nil,
+	}
+	if err != nil {
+		in.Value = err.Error()
+	} else {
+		in.Value = strings.TrimSpace(string(out))
+	}
+	e.record(in)
+	return string(out), err == nil
+}
+
+// WithPassthrough wraps the queries sent to the terminal so that they pass through the multiplexer.
+// See Multiplexer.Wrap.
+func WithPassthrough(m Multiplexer) ProbeOption {
+	return func(c *probeConfig) {
+		c.multiplexer = m
+	}
+}
@@ -0,0 +1,237 @@
+package termcolor
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTmux returns a CommandRunner that answers tmux commands with canned outputs.
+func fakeTmux(options, clientTerm string) CommandRunner {
+	return func(name string, args ...string) ([]byte, error) {
+		if name != "tmux" {
+			return nil, errors.New("unexpected command")
+		}
+		switch args[0] {
+		case "show-options":
+			return []byte(options), nil
+		case "display-message":
+			return []byte(clientTerm + "\n"), nil
+		default:
+			return nil, errors.New("unexpected tmux command")
+		}
+	}
+}
+
+func TestDetector_Multiplexer(t *testing.T) {
+	testCases := map[string]struct {
+		envs map[string]string
+		run  CommandRunner
+
+		wantedMultiplexer Multiplexer
+		wantedLevel       Level
+	}{
+		"no multiplexer": {
+			envs:              map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"},
+			wantedMultiplexer: NoMultiplexer,
+			wantedLevel:       Level16M,
+		},
+		"tmux with RGB in terminal-features": {
+			envs: map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			run: fakeTmux(
+				"terminal-features[0] xterm*:clipboard:ccolour:cstyle:focus:title\n"+
+					"terminal-features[1] alacritty:RGB\n",
+				"alacritty"),
+			wantedMultiplexer: Tmux,
+			wantedLevel:       Level16M,
+		},
+		"tmux with Tc in terminal-overrides": {
+			envs:              map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			run:               fakeTmux(`terminal-overrides[0] "xterm*:XT,*256col*:Tc"`, "xterm-256color"),
+			wantedMultiplexer: Tmux,
+			wantedLevel:       Level16M,
+		},
+		"tmux with RGB for another terminal": {
+			envs:              map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			run:               fakeTmux("terminal-features[0] alacritty:RGB\n", "xterm-256color"),
+			wantedMultiplexer: Tmux,
+			wantedLevel:       Level256,
+		},
+		"tmux ignores the variables of the outer terminal": {
+			envs: map[string]string{
+				"TERM":                 "screen-256color",
+				"TMUX":                 "/tmp/tmux-1000/default,1234,0",
+				"COLORTERM":            "truecolor",
+				"TERM_PROGRAM":         "iTerm.app",
+				"TERM_PROGRAM_VERSION": "3.4.0",
+			},
+			run:               fakeTmux("", "xterm-256color"),
+			wantedMultiplexer: Tmux,
+			wantedLevel:       Level256,
+		},
+		"tmux can't be run": {
+			envs: map[string]string{"TERM": "tmux-256color", "TMUX": "/tmp/tmux-1000/default,1234,0"},
+			run: func(name string, args ...string) ([]byte, error) {
+				return nil, errors.New("exec: \"tmux\": executable file not found in $PATH")
+			},
+			wantedMultiplexer: Tmux,
+			wantedLevel:       Level256,
+		},
+		"screen": {
+			envs:              map[string]string{"TERM": "screen", "STY": "1234.pts-0.host", "COLORTERM": "truecolor"},
+			wantedMultiplexer: Screen,
+			wantedLevel:       LevelBasic,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return true
+				}),
+				WithTerminfoDirs(),
+				WithCommandRunner(tc.run),
+			)
+
+			// When
+			m := d.Multiplexer()
+			l := d.Level(os.Stdout)
+
+			// Then
+			if m != tc.wantedMultiplexer {
+				t.Errorf("expected multiplexer %v, got %v", tc.wantedMultiplexer, m)
+			}
+			if l != tc.wantedLevel {
+				t.Errorf("expected level %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestDetector_Explain_Multiplexer(t *testing.T) {
+	// Given
+	d := NewDetector(
+		WithArgs(nil),
+		WithEnv(map[string]string{"TMUX": "/tmp/tmux-1000/default,1234,0"}),
+		WithTerminalChecker(func(fd uintptr) bool {
+			return true
+		}),
+		WithTerminfoDirs(),
+		WithCommandRunner(fakeTmux("terminal-features[0] xterm*:RGB", "xterm-256color")),
+	)
+
+	// When
+	decider, _ := d.Explain(os.Stdout).Decider()
+
+	// Then
+	wanted := Step{
+		Rule: "lookupMultiplexer",
+		Inputs: []Input{
+			{Source: SourceEnv, Name: "TMUX", Value: "/tmp/tmux-1000/default,1234,0", Present: true},
+			{Source: SourceCommand, Name: "tmux show-options -s", Value: "terminal-features[0] xterm*:RGB", Present: true},
+			{Source: SourceCommand, Name: "tmux display-message -p #{client_termname}", Value: "xterm-256color", Present: true},
+		},
+		Decided: true,
+		Level:   Level16M,
+	}
+	if !reflect.DeepEqual(decider, wanted) {
+		t.Errorf("expected %+v, got %+v", wanted, decider)
+	}
+}
+
+func TestDetector_Level_RunsTmuxOnce(t *testing.T) {
+	// Given
+	env := map[string]string{"TMUX": "/tmp/tmux-1000/default,1234,0"}
+	runs := 0
+	tmux := fakeTmux("", "")
+	d := NewDetector(
+		WithArgs(nil),
+		WithLookupEnv(func(key string) (string, bool) {
+			v, ok := env[key]
+			return v, ok
+		}),
+		WithTerminalChecker(func(fd uintptr) bool {
+			return true
+		}),
+		WithTerminfoDirs(),
+		WithCommandRunner(func(name string, args ...string) ([]byte, error) {
+			runs++
+			return tmux(name, args...)
+		}),
+	)
+
+	// When
+	d.Level(os.Stdout)
+	d.Level(os.Stdout)
+	decision := d.Explain(os.Stdout)
+
+	// Then
+	if runs != 2 {
+		t.Errorf("expected tmux to be asked for its options once, got %d commands", runs)
+	}
+	if !strings.Contains(decision.String(), "tmux show-options -s") {
+		t.Errorf("expected the remembered commands to be explained, got %q", decision.String())
+	}
+
+	// When
+	env["TMUX"] = "/tmp/tmux-1000/other,5678,0"
+	d.Level(os.Stdout)
+
+	// Then
+	if runs != 4 {
+		t.Errorf("expected another tmux server to be asked for its options, got %d commands", runs)
+	}
+}
+
+func TestMultiplexer_Wrap(t *testing.T) {
+	testCases := map[string]struct {
+		m   Multiplexer
+		seq string
+
+		wanted string
+	}{
+		"no multiplexer": {
+			m:      NoMultiplexer,
+			seq:    "\x1b]11;?\x1b\\",
+			wanted: "\x1b]11;?\x1b\\",
+		},
+		"tmux doubles escape characters": {
+			m:      Tmux,
+			seq:    "\x1b]11;?\x1b\\",
+			wanted: "\x1bPtmux;\x1b\x1b]11;?\x1b\x1b\\\x1b\\",
+		},
+		"screen": {
+			m:      Screen,
+			seq:    "\x1b]11;?\a",
+			wanted: "\x1bP\x1b]11;?\a\x1b\\",
+		},
+		"screen splits long sequences": {
+			m:      Screen,
+			seq:    strings.Repeat("a", screenChunkLen+1),
+			wanted: "\x1bP" + strings.Repeat("a", screenChunkLen) + "\x1b\\\x1bPa\x1b\\",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := tc.m.Wrap(tc.seq); got != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, got)
+			}
+			if needed := tc.m != NoMultiplexer; tc.m.NeedsPassthrough() != needed {
+				t.Errorf("expected NeedsPassthrough to be %v", needed)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package termcolor
+
+// ColorEnv is an environment variable that forces or disables colors.
+type ColorEnv int
+
+// Environment variables that can force or disable colors.
+const (
+	// EnvForceColor is FORCE_COLOR. It forces the level given by its value: "0" or "false" disables colors, "2"
+	// forces 256 colors, "3" forces true colors and other values force basic colors.
+	EnvForceColor ColorEnv = iota + 1
+	// EnvNoColor is NO_COLOR. Any value other than an empty string disables colors.
+	// See https://no-color.org
+	EnvNoColor
+	// EnvCLIColorForce is CLICOLOR_FORCE. Any value other than an empty string or "0" forces basic colors.
+	// See https://bixense.com/clicolors/
+	EnvCLIColorForce
+	// EnvCLIColor is CLICOLOR. The value "0" disables colors, other values leave the decision to the other rules.
+	EnvCLIColor
+)
+
+// String returns the name of the environment variable.
+func (v ColorEnv) String() string {
+	switch v {
+	case EnvForceColor:
+		return "FORCE_COLOR"
+	case EnvNoColor:
+		return "NO_COLOR"
+	case EnvCLIColorForce:
+		return "CLICOLOR_FORCE"
+	case EnvCLIColor:
+		return "CLICOLOR"
+	default:
+		return "unknown"
+	}
+}
+
+// ColorPolicy is the precedence of the environment variables that force or disable colors.
+// The first variable of the policy that forces or disables colors decides, and the variables that aren't part of
+// the policy are ignored. Flags such as --no-color or --color=256 always take priority over the policy.
+type ColorPolicy []ColorEnv
+
+// Preset policies.
+var (
+	// DefaultPolicy honors all the variables, with FORCE_COLOR first since it's usually set for a single
+	// invocation, while NO_COLOR is usually set for a whole session.
+	DefaultPolicy = ColorPolicy{EnvForceColor, EnvNoColor, EnvCLIColorForce, EnvCLIColor}
+	// ChalkPolicy only honors FORCE_COLOR, like chalk's supports-color module.
+	ChalkPolicy = ColorPolicy{EnvForceColor}
+	// NoColorPolicy gives NO_COLOR precedence over the variables that force colors, as required by no-color.org.
+	NoColorPolicy = ColorPolicy{EnvNoColor, EnvForceColor, EnvCLIColorForce, EnvCLIColor}
+	// BSDPolicy only honors CLICOLOR_FORCE and CLICOLOR, like the ls command of BSD systems.
+	BSDPolicy = ColorPolicy{EnvCLIColorForce, EnvCLIColor}
+)
+
+// WithColorPolicy sets the precedence of the environment variables that force or disable colors.
+// The default is DefaultPolicy.
+func WithColorPolicy(p ColorPolicy) DetectorOption {
+	return func(d *Detector) {
+		d.policy = p
+	}
+}
+
+// lookupColorEnv returns the level forced by the first variable of the detector's policy that forces or disables
+// colors. LevelNone means that colors are disabled. If no variable decides, then returns false.
+func (e *detection) lookupColorEnv() (Level, bool) {
+	for _, v := range e.d.policy {
+		if l, ok := e.lookupColorVar(v); ok {
+			return l, true
+		}
+	}
+	return LevelNone, false
+}
+
+func (e *detection) lookupColorVar(v ColorEnv) (Level, bool) {
+	val, ok := e.lookupEnv(v.String())
+	if !ok {
+		return LevelNone, false
+	}
+	switch v {
+	case EnvForceColor:
+		return forceColorValue(val), true
+	case EnvNoColor:
+		// An empty NO_COLOR is ignored.
+		return LevelNone, val != ""
+	case EnvCLIColorForce:
+		return LevelBasic, val != "" && val != "0"
+	case EnvCLIColor:
+		return LevelNone, val == "0"
+	default:
+		return LevelNone, false
+	}
+}
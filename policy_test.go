@@ -0,0 +1,124 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestWithColorPolicy(t *testing.T) {
+	testCases := map[string]struct {
+		policy     ColorPolicy
+		args       []string
+		envs       map[string]string
+		isTerminal bool
+
+		wantedLevel Level
+	}{
+		"NO_COLOR disables colors": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"NO_COLOR": "1", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"an empty NO_COLOR is ignored": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"NO_COLOR": "", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"FORCE_COLOR takes precedence over NO_COLOR by default": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"},
+			wantedLevel: Level16M,
+		},
+		"FORCE_COLOR=0 disables colors": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"FORCE_COLOR": "0", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"flags take precedence over NO_COLOR": {
+			policy:      DefaultPolicy,
+			args:        []string{"cli", "--color"},
+			envs:        map[string]string{"NO_COLOR": "1", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"CLICOLOR_FORCE when not a terminal": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"CLICOLOR_FORCE": "1", "TERM": "xterm-256color"},
+			wantedLevel: Level256,
+		},
+		"CLICOLOR_FORCE=0 is ignored": {
+			policy: DefaultPolicy,
+			envs:   map[string]string{"CLICOLOR_FORCE": "0", "TERM": "xterm-256color"},
+		},
+		"CLICOLOR=0 disables colors": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"CLICOLOR": "0", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: LevelNone,
+		},
+		"CLICOLOR=1 leaves the decision to the terminal": {
+			policy:      DefaultPolicy,
+			envs:        map[string]string{"CLICOLOR": "1", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"chalk ignores NO_COLOR": {
+			policy:      ChalkPolicy,
+			envs:        map[string]string{"NO_COLOR": "1", "TERM": "xterm-256color"},
+			isTerminal:  true,
+			wantedLevel: Level256,
+		},
+		"chalk ignores CLICOLOR_FORCE": {
+			policy: ChalkPolicy,
+			envs:   map[string]string{"CLICOLOR_FORCE": "1", "TERM": "xterm-256color"},
+		},
+		"no-color.org gives NO_COLOR precedence over FORCE_COLOR": {
+			policy:     NoColorPolicy,
+			envs:       map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"},
+			isTerminal: true,
+		},
+		"BSD ignores FORCE_COLOR": {
+			policy: BSDPolicy,
+			envs:   map[string]string{"FORCE_COLOR": "3", "TERM": "xterm-256color"},
+		},
+		"BSD gives CLICOLOR_FORCE precedence over CLICOLOR": {
+			policy:      BSDPolicy,
+			envs:        map[string]string{"CLICOLOR_FORCE": "1", "CLICOLOR": "0"},
+			wantedLevel: LevelBasic,
+		},
+		"custom policy": {
+			policy:     ColorPolicy{EnvCLIColor, EnvForceColor},
+			envs:       map[string]string{"CLICOLOR": "0", "FORCE_COLOR": "3"},
+			isTerminal: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(tc.args),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return tc.isTerminal
+				}),
+				WithTerminfoDirs(),
+				WithColorPolicy(tc.policy),
+			)
+
+			// When
+			l := d.Level(os.Stdout)
+
+			// Then
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
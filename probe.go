@@ -0,0 +1,156 @@
+package termcolor
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/efekarakus/termcolor/ansi"
+)
+
+// Errors returned when probing the terminal.
+var (
+	// ErrProbeTimeout is returned when the terminal doesn't respond before the timeout.
+	ErrProbeTimeout = errors.New("termcolor: terminal did not respond before the timeout")
+	// ErrProbeUnsupported is returned when the terminal answers the device attributes sentinel
+	// but not the query itself.
+	ErrProbeUnsupported = errors.New("termcolor: terminal does not support the query")
+)
+
+// DefaultProbeTimeout is the time to wait for the terminal to respond if no timeout is provided.
+const DefaultProbeTimeout = 500 * time.Millisecond
+
+// ProbeOption configures how the terminal is queried.
+type ProbeOption func(c *probeConfig)
+
+type probeConfig struct {
+	timeout     time.Duration
+	multiplexer Multiplexer
+}
+
+// WithProbeTimeout sets how long to wait for the terminal to respond.
+func WithProbeTimeout(timeout time.Duration) ProbeOption {
+	return func(c *probeConfig) {
+		c.timeout = timeout
+	}
+}
+
+func newProbeConfig(opts []ProbeOption) *probeConfig {
+	c := &probeConfig{
+		timeout: DefaultProbeTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Escape sequences used to probe the terminal.
+// See https://invisible-island.net/xterm/ctlseqs/ctlseqs.html
+const (
+	// probeSGR sets a 24-bit background color that's unlikely to be in any palette.
+	probeSGR = "\x1b[48;2;1;2;3m"
+	// requestSGR asks the terminal for its current graphic rendition with DECRQSS.
+	requestSGR = "\x1bP$qm\x1b\\"
+	// resetSGR resets the graphic rendition.
+	resetSGR = "\x1b[0m"
+	// requestDA1 asks the terminal for its primary device attributes.
+	// Every terminal answers it, so the response marks the end of the answers to the previous queries.
+	requestDA1 = "\x1b[c"
+)
+
+// ProbeLevel confirms the color level of a terminal by asking it what it does with a 24-bit color.
+// The file descriptor must be a terminal that can be read from and written to, such as os.Stdin or "/dev/tty".
+//
+// The terminal is temporarily put in raw mode, a 24-bit background color is set and read back with a DECRQSS
+// request, and the graphic rendition is reset. A terminal that doesn't support DECRQSS is detected by its answer
+// to a trailing primary device attributes request, in which case ErrProbeUnsupported is returned.
+// If the terminal doesn't respond at all before the timeout, then ErrProbeTimeout is returned.
+func ProbeLevel(f FileDescriptor, opts ...ProbeOption) (Level, error) {
+	c := newProbeConfig(opts)
+	request := []byte(c.multiplexer.Wrap(probeSGR + requestSGR + resetSGR + requestDA1))
+	resp, err := queryTerminal(int(f.Fd()), request, c.timeout, hasDA1Response)
+	if err != nil && err != ErrProbeTimeout {
+		return LevelNone, err
+	}
+	payload, ok := decrqssResponse(resp)
+	if !ok {
+		if err != nil {
+			return LevelNone, err
+		}
+		return LevelNone, ErrProbeUnsupported
+	}
+	l, ok := sgrBackgroundLevel(payload)
+	if !ok {
+		return LevelNone, ErrProbeUnsupported
+	}
+	return l, nil
+}
+
+// hasDA1Response returns true if resp contains an answer to the primary device attributes request.
+// The answer has the form "CSI ? Ps ; ... c".
+func hasDA1Response(resp []byte) bool {
+	for _, tok := range ansi.Tokenize(resp) {
+		if tok.Kind == ansi.CSI && tok.Prefix == '?' && tok.Final == 'c' && len(tok.Intermediates) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// decrqssResponse returns the data of the first DECRQSS response in resp.
+// For example, the response "DCS 1 $ r 0;48:2::1:2:3 m ST" returns "0;48:2::1:2:3m".
+func decrqssResponse(resp []byte) (string, bool) {
+	for _, tok := range ansi.Tokenize(resp) {
+		if tok.Kind == ansi.DCS && string(tok.Intermediates) == "$" && tok.Final == 'r' && endsWithST(tok.Raw) {
+			return string(tok.Data), true
+		}
+	}
+	return "", false
+}
+
+// endsWithST returns true if the control string raw is terminated by ST rather than interrupted.
+func endsWithST(raw []byte) bool {
+	return bytes.HasSuffix(raw, []byte("\x1b\\")) || bytes.HasSuffix(raw, []byte("\u009c"))
+}
+
+// sgrBackgroundLevel returns the color level of the background color in a DECRQSS response to an SGR request.
+// If the response has no background color, then returns false.
+func sgrBackgroundLevel(payload string) (Level, bool) {
+	if !strings.HasSuffix(payload, "m") {
+		return LevelNone, false
+	}
+	params := strings.FieldsFunc(strings.TrimSuffix(payload, "m"), func(r rune) bool {
+		return r == ';'
+	})
+	for i := 0; i < len(params); i++ {
+		// Parameters may use colon separated subparameters such as "48:2::1:2:3".
+		sub := strings.Split(params[i], ":")
+		n, err := strconv.Atoi(sub[0])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 48:
+			if len(sub) == 1 {
+				// Semicolon separated form such as "48;2;1;2;3".
+				sub = append(sub, params[i+1:]...)
+			}
+			if len(sub) < 2 {
+				return LevelNone, false
+			}
+			switch sub[1] {
+			case "2":
+				return Level16M, true
+			case "5":
+				return Level256, true
+			}
+			return LevelNone, false
+		case n >= 40 && n <= 47, n >= 100 && n <= 107:
+			return LevelBasic, true
+		}
+	}
+	return LevelNone, false
+}
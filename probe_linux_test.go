@@ -0,0 +1,89 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestProbeLevel(t *testing.T) {
+	testCases := map[string]struct {
+		answers map[string]string
+
+		wantedLevel Level
+		wantedErr   error
+	}{
+		"terminal with true colors": {
+			answers: map[string]string{
+				requestSGR: "\x1bP1$r0;48:2::1:2:3m\x1b\\",
+				requestDA1: "\x1b[?62;22c",
+			},
+			wantedLevel: Level16M,
+		},
+		"terminal that downsamples to 256 colors": {
+			answers: map[string]string{
+				requestSGR: "\x1bP1$r0;48;5;16m\x1b\\",
+				requestDA1: "\x1b[?62;22c",
+			},
+			wantedLevel: Level256,
+		},
+		"terminal without DECRQSS": {
+			answers: map[string]string{
+				requestDA1: "\x1b[?1;2c",
+			},
+			wantedErr: ErrProbeUnsupported,
+		},
+		"terminal that rejects the request": {
+			answers: map[string]string{
+				requestSGR: "\x1bP0$r\x1b\\",
+				requestDA1: "\x1b[?1;2c",
+			},
+			wantedErr: ErrProbeUnsupported,
+		},
+		"terminal that doesn't respond": {
+			wantedErr: ErrProbeTimeout,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			master, slave := openTestPTY(t)
+			defer slave.Close()
+			defer master.Close()
+			fakeTerminal(master, tc.answers)
+
+			// When
+			l, err := ProbeLevel(slave, WithProbeTimeout(100*time.Millisecond))
+
+			// Then
+			if err != tc.wantedErr {
+				t.Fatalf("expected error %v, got %v", tc.wantedErr, err)
+			}
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestProbeLevel_NotATerminal(t *testing.T) {
+	// Given
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("create pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	// When
+	_, err = ProbeLevel(r)
+
+	// Then
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,135 @@
+package termcolor
+
+import (
+	"testing"
+)
+
+func TestSgrBackgroundLevel(t *testing.T) {
+	testCases := map[string]struct {
+		payload string
+
+		wantedLevel Level
+		wantedOK    bool
+	}{
+		"colon separated truecolor": {
+			payload:     "0;48:2::1:2:3m",
+			wantedLevel: Level16M,
+			wantedOK:    true,
+		},
+		"semicolon separated truecolor": {
+			payload:     "48;2;1;2;3m",
+			wantedLevel: Level16M,
+			wantedOK:    true,
+		},
+		"downsampled to 256 colors": {
+			payload:     "0;48;5;16m",
+			wantedLevel: Level256,
+			wantedOK:    true,
+		},
+		"downsampled to basic colors": {
+			payload:     "0;40m",
+			wantedLevel: LevelBasic,
+			wantedOK:    true,
+		},
+		"downsampled to bright basic colors": {
+			payload:     "100m",
+			wantedLevel: LevelBasic,
+			wantedOK:    true,
+		},
+		"no background": {
+			payload: "0m",
+		},
+		"truncated background": {
+			payload: "48m",
+		},
+		"not an SGR response": {
+			payload: "1\"p",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			l, ok := sgrBackgroundLevel(tc.payload)
+
+			// Then
+			if ok != tc.wantedOK {
+				t.Fatalf("expected ok %v, got %v", tc.wantedOK, ok)
+			}
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestDecrqssResponse(t *testing.T) {
+	testCases := map[string]struct {
+		resp string
+
+		wantedPayload string
+		wantedOK      bool
+	}{
+		"valid response": {
+			resp:          "\x1bP1$r0;48:2::1:2:3m\x1b\\\x1b[?62;22c",
+			wantedPayload: "0;48:2::1:2:3m",
+			wantedOK:      true,
+		},
+		"skips other strings": {
+			resp:          "\x1bP>|xterm(388)\x1b\\\x1bP1$r0m\x1b\\",
+			wantedPayload: "0m",
+			wantedOK:      true,
+		},
+		"unterminated string": {
+			resp: "\x1bP1$r0;48:2::1:2:3m",
+		},
+		"only device attributes": {
+			resp: "\x1b[?62;22c",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			payload, ok := decrqssResponse([]byte(tc.resp))
+
+			// Then
+			if ok != tc.wantedOK {
+				t.Fatalf("expected ok %v, got %v", tc.wantedOK, ok)
+			}
+			if payload != tc.wantedPayload {
+				t.Errorf("expected %q, got %q", tc.wantedPayload, payload)
+			}
+		})
+	}
+}
+
+func TestHasDA1Response(t *testing.T) {
+	testCases := map[string]struct {
+		resp   string
+		wanted bool
+	}{
+		"complete response":     {resp: "\x1b[?62;22c", wanted: true},
+		"after other responses": {resp: "\x1bP1$r0m\x1b\\\x1b[?1;2c", wanted: true},
+		"partial response":      {resp: "\x1b[?62;2"},
+		"other private csi":     {resp: "\x1b[?1u"},
+		"other then complete":   {resp: "\x1b[?1u\x1b[?6c", wanted: true},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := hasDA1Response([]byte(tc.resp)); got != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// +build linux
+
+package termcolor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// openPTY returns the master and slave ends of a new pseudo-terminal.
+func openPTY() (master, slave *os.File, err error) {
+	master, err = os.OpenFile("/dev/ptmx", os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := unix.IoctlSetPointerInt(int(master.Fd()), unix.TIOCSPTLCK, 0); err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("termcolor: unlock pty: %v", err)
+	}
+	n, err := unix.IoctlGetInt(int(master.Fd()), unix.TIOCGPTN)
+	if err != nil {
+		master.Close()
+		return nil, nil, fmt.Errorf("termcolor: get pty number: %v", err)
+	}
+	slave, err = os.OpenFile(fmt.Sprintf("/dev/pts/%d", n), os.O_RDWR|unix.O_NOCTTY, 0)
+	if err != nil {
+		master.Close()
+		return nil, nil, err
+	}
+	return master, slave, nil
+}
+
+// startPTY starts cmd with its standard output and error connected to a new pseudo-terminal, and copies the output
+// of the terminal to the original cmd.Stdout.
+func startPTY(cmd *exec.Cmd) (func() error, error) {
+	master, slave, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	// Keep line feeds as is, since the output is copied to a writer that isn't necessarily a terminal.
+	if t, err := unix.IoctlGetTermios(int(slave.Fd()), ioctlReadTermios); err == nil {
+		t.Oflag &^= unix.ONLCR
+		unix.IoctlSetTermios(int(slave.Fd()), ioctlWriteTermios, t)
+	}
+
+	out := cmd.Stdout
+	if out == nil {
+		out = ioutil.Discard
+	}
+	cmd.Stdout = slave
+	cmd.Stderr = slave
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	cmd.SysProcAttr.Setctty = true
+	cmd.SysProcAttr.Ctty = 1 // The standard output of the child.
+	err = cmd.Start()
+	// The child has its own copy of the slave: the master reads EIO once it's closed there.
+	slave.Close()
+	if err != nil {
+		master.Close()
+		return nil, err
+	}
+
+	copied := make(chan error, 1)
+	go func() {
+		_, err := io.Copy(out, master)
+		if errors.Is(err, syscall.EIO) {
+			err = nil
+		}
+		copied <- err
+	}()
+	return func() error {
+		err := cmd.Wait()
+		copyErr := <-copied
+		master.Close()
+		if err != nil {
+			return err
+		}
+		return copyErr
+	}, nil
+}
@@ -0,0 +1,46 @@
+package termcolor
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// openTestPTY returns the master and slave ends of a new pseudo-terminal, or skips the test if there is none.
+func openTestPTY(t *testing.T) (master, slave *os.File) {
+	t.Helper()
+	master, slave, err := openPTY()
+	if err != nil {
+		t.Skipf("pseudo-terminals are not available: %v", err)
+	}
+	return master, slave
+}
+
+// fakeTerminal answers the queries written to the slave end of a pseudo-terminal until the master is closed.
+// Each query found in answers is answered in the order it was received, other input is ignored.
+func fakeTerminal(master *os.File, answers map[string]string) {
+	go func() {
+		var pending []byte
+		buf := make([]byte, 256)
+		for {
+			n, err := master.Read(buf)
+			if err != nil {
+				return
+			}
+			pending = append(pending, buf[:n]...)
+			for {
+				query, pos := "", -1
+				for q := range answers {
+					if i := bytes.Index(pending, []byte(q)); i != -1 && (pos == -1 || i < pos) {
+						query, pos = q, i
+					}
+				}
+				if pos == -1 {
+					break
+				}
+				master.Write([]byte(answers[query]))
+				pending = pending[pos+len(query):]
+			}
+		}
+	}()
+}
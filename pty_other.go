@@ -0,0 +1,14 @@
+// +build !linux
+
+package termcolor
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// startPTY is not supported on platforms other than Linux.
+func startPTY(cmd *exec.Cmd) (func() error, error) {
+	return nil, fmt.Errorf("termcolor: pseudo-terminals are not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,14 @@
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package termcolor
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+)
+
+// queryTerminal is not supported on platforms without termios.
+func queryTerminal(fd int, request []byte, timeout time.Duration, done func(resp []byte) bool) ([]byte, error) {
+	return nil, fmt.Errorf("termcolor: querying the terminal is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,90 @@
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package termcolor
+
+import (
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// queryTerminal writes request to the terminal in raw mode and reads the responses until done returns true.
+// The responses read so far are returned if the timeout expires first.
+func queryTerminal(fd int, request []byte, timeout time.Duration, done func(resp []byte) bool) ([]byte, error) {
+	restore, err := makeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+
+	if err := writeAll(fd, request); err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	var resp []byte
+	buf := make([]byte, 256)
+	for !done(resp) {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return resp, ErrProbeTimeout
+		}
+		fds := []unix.PollFd{{Fd: int32(fd), Events: unix.POLLIN}}
+		n, err := unix.Poll(fds, int((remaining+time.Millisecond-1)/time.Millisecond))
+		if err == unix.EINTR || n == 0 {
+			continue
+		}
+		if err != nil {
+			return resp, err
+		}
+		n, err = unix.Read(fd, buf)
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if err != nil {
+			return resp, err
+		}
+		if n == 0 {
+			// The other end of the terminal was closed.
+			return resp, ErrProbeTimeout
+		}
+		resp = append(resp, buf[:n]...)
+	}
+	return resp, nil
+}
+
+// makeRaw puts the terminal in raw mode so that responses are neither echoed nor line buffered.
+// The returned function restores the previous state of the terminal.
+func makeRaw(fd int) (func(), error) {
+	old, err := unix.IoctlGetTermios(fd, ioctlReadTermios)
+	if err != nil {
+		return nil, err
+	}
+	raw := *old
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlWriteTermios, &raw); err != nil {
+		return nil, err
+	}
+	return func() {
+		unix.IoctlSetTermios(fd, ioctlWriteTermios, old)
+	}, nil
+}
+
+func writeAll(fd int, b []byte) error {
+	for len(b) > 0 {
+		n, err := unix.Write(fd, b)
+		if err == unix.EINTR || err == unix.EAGAIN {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		b = b[n:]
+	}
+	return nil
+}
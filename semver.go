@@ -0,0 +1,162 @@
+package termcolor
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// version is a version number such as 9.1.0 split into its numeric components.
+type version []int
+
+// parseVersion parses the leading numeric components of s, such as "9.1.0" in "9.1.0 (build 32523)".
+// A "v" prefix is ignored. If s doesn't start with a number, then returns false.
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	var v version
+	for {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		if i == 0 {
+			break
+		}
+		n, err := strconv.Atoi(s[:i])
+		if err != nil {
+			return nil, false
+		}
+		v = append(v, n)
+		if i == len(s) || s[i] != '.' {
+			break
+		}
+		s = s[i+1:]
+	}
+	return v, len(v) > 0
+}
+
+// compare returns -1, 0 or 1 if v is lower than, equal to or greater than o. Missing components are zeros.
+func (v version) compare(o version) int {
+	for i := 0; i < len(v) || i < len(o); i++ {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(o) {
+			b = o[i]
+		}
+		switch {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+	return 0
+}
+
+// requirement is a comparison with a version, such as ">=9.1".
+type requirement struct {
+	op string
+	v  version
+}
+
+// constraint is a set of alternatives, each made of requirements that must all be satisfied.
+type constraint [][]requirement
+
+// constraintOps holds the operators of requirements, longest first.
+var constraintOps = []string{">=", "<=", "!=", ">", "<", "=", "~", "^"}
+
+// parseConstraint parses a constraint such as ">=9.1", ">=0.50 <0.70" or "^3 || >=4.2".
+// Requirements are separated by spaces or commas and alternatives by "||". A version without an operator must be
+// equal. The "~" operator allows newer patch versions and "^" allows newer minor versions.
+// An empty constraint is satisfied by any version.
+func parseConstraint(s string) (constraint, error) {
+	var c constraint
+	if strings.TrimSpace(s) == "" {
+		return c, nil
+	}
+	for _, alt := range strings.Split(s, "||") {
+		fields := strings.FieldsFunc(alt, func(r rune) bool {
+			return r == ' ' || r == ','
+		})
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("termcolor: empty alternative in version constraint %q", s)
+		}
+		var reqs []requirement
+		for i := 0; i < len(fields); i++ {
+			op, rest := "=", fields[i]
+			for _, o := range constraintOps {
+				if strings.HasPrefix(rest, o) {
+					op, rest = o, strings.TrimPrefix(rest, o)
+					break
+				}
+			}
+			if rest == "" && i+1 < len(fields) {
+				// The operator is separated from the version, such as ">= 9.1".
+				i++
+				rest = fields[i]
+			}
+			v, ok := parseVersion(rest)
+			if !ok {
+				return nil, fmt.Errorf("termcolor: invalid version %q in version constraint %q", rest, s)
+			}
+			reqs = append(reqs, requirement{op: op, v: v})
+		}
+		c = append(c, reqs)
+	}
+	return c, nil
+}
+
+// check returns true if v satisfies the constraint.
+func (c constraint) check(v version) bool {
+	if len(c) == 0 {
+		return true
+	}
+	for _, reqs := range c {
+		ok := true
+		for _, req := range reqs {
+			if !req.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (r requirement) check(v version) bool {
+	cmp := v.compare(r.v)
+	switch r.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case "<":
+		return cmp < 0
+	case "~":
+		if len(r.v) < 2 {
+			return cmp >= 0 && v.compare(r.v.next(0)) < 0
+		}
+		return cmp >= 0 && v.compare(r.v.next(1)) < 0
+	case "^":
+		return cmp >= 0 && v.compare(r.v.next(0)) < 0
+	default:
+		return cmp == 0
+	}
+}
+
+// next returns the smallest version whose i-th component is greater than v's, such as 1.3 for 1.2.5 and i=1.
+func (v version) next(i int) version {
+	n := make(version, i+1)
+	copy(n, v)
+	n[i]++
+	return n
+}
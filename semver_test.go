@@ -0,0 +1,78 @@
+package termcolor
+
+import (
+	"testing"
+)
+
+func TestConstraint_Check(t *testing.T) {
+	testCases := map[string]struct {
+		constraint string
+		version    string
+
+		wanted bool
+	}{
+		"empty constraint":           {constraint: "", version: "1.0", wanted: true},
+		"greater or equal":           {constraint: ">=9.1", version: "9.1.0 (build 32523)", wanted: true},
+		"lower than minimum":         {constraint: ">=9.1", version: "9.0.5 (build 32523)", wanted: false},
+		"separated operator":         {constraint: ">= 9.1", version: "10", wanted: true},
+		"range":                      {constraint: ">=0.50 <0.70", version: "0.64.1", wanted: true},
+		"outside of range":           {constraint: ">=0.50, <0.70", version: "0.70", wanted: false},
+		"alternatives":               {constraint: "<1 || >=3.2", version: "3.4", wanted: true},
+		"no alternative matches":     {constraint: "<1 || >=3.2", version: "2", wanted: false},
+		"exact":                      {constraint: "1.2", version: "v1.2.0", wanted: true},
+		"not equal":                  {constraint: "!=1.2", version: "1.2.0", wanted: false},
+		"tilde allows patches":       {constraint: "~1.2", version: "1.2.9", wanted: true},
+		"tilde rejects minors":       {constraint: "~1.2", version: "1.3.0", wanted: false},
+		"caret allows minors":        {constraint: "^3", version: "3.9.1", wanted: true},
+		"caret rejects majors":       {constraint: "^3.1", version: "4.0", wanted: false},
+		"strict greater than":        {constraint: ">3", version: "3.0.0", wanted: false},
+		"lower or equal than":        {constraint: "<=3", version: "3.0.0", wanted: true},
+		"missing components are 0s":  {constraint: "=3.0.0", version: "3", wanted: true},
+		"greater major than minimum": {constraint: ">=9.1", version: "10.0", wanted: true},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			c, err := parseConstraint(tc.constraint)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			v, ok := parseVersion(tc.version)
+			if !ok {
+				t.Fatalf("expected %q to be a version", tc.version)
+			}
+
+			// When
+			got := c.check(v)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestParseConstraint_Errors(t *testing.T) {
+	testCases := map[string]string{
+		"missing version":     ">=",
+		"invalid version":     ">=abc",
+		"empty alternative":   ">=1 ||",
+		"trailing characters": "1.2 x",
+	}
+
+	for name, constraint := range testCases {
+		constraint := constraint
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := parseConstraint(constraint); err == nil {
+				t.Errorf("expected an error for %q", constraint)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package termcolor
+
+// remoteStdout is the standard output of a remote session.
+type remoteStdout struct{}
+
+func (remoteStdout) Fd() uintptr {
+	return 1
+}
+
+// SessionLevel returns the color level of a remote session, such as an SSH channel or a web terminal, from the
+// terminal type and the environment variables sent by its client. isPTY is true if the client requested a
+// pseudo-terminal. See NewSessionDetector.
+func SessionLevel(term string, env map[string]string, isPTY bool) Level {
+	return NewSessionDetector(term, env, isPTY).Level(remoteStdout{})
+}
+
+// NewSessionDetector returns a Detector for a remote session, such as an SSH channel or a web terminal.
+// The level is computed with the same rules as SupportLevel, but only from the terminal type, such as the TERM of an
+// SSH pty request, and the environment variables sent by the client. term takes precedence over the TERM variable
+// of env if it's not empty. Every file descriptor is a terminal if isPTY is true.
+//
+// The detector doesn't read the arguments, the environment or the console of the current process, and doesn't run
+// commands such as tmux. Terminfo entries are only searched in the system directories, since the ones named by
+// TERMINFO or HOME belong to the client's machine. Options such as WithColorMode or WithColorPolicy are applied
+// afterwards.
+func NewSessionDetector(term string, env map[string]string, isPTY bool, opts ...DetectorOption) *Detector {
+	sessionEnv := make(map[string]string, len(env)+1)
+	for k, v := range env {
+		sessionEnv[k] = v
+	}
+	if term != "" {
+		sessionEnv["TERM"] = term
+	}
+	d := NewDetector(
+		WithEnv(sessionEnv),
+		WithArgs(nil),
+		WithArgSniffing(false),
+		WithTerminalChecker(func(uintptr) bool {
+			return isPTY
+		}),
+		WithCommandRunner(nil),
+	)
+	d.remote = true
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
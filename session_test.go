@@ -0,0 +1,117 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestSessionLevel(t *testing.T) {
+	testCases := map[string]struct {
+		term  string
+		env   map[string]string
+		isPTY bool
+
+		wantedLevel Level
+	}{
+		"pty with xterm-256color": {
+			term:        "xterm-256color",
+			isPTY:       true,
+			wantedLevel: Level256,
+		},
+		"pty with true colors sent by the client": {
+			term: "xterm-256color",
+			env: map[string]string{
+				"COLORTERM": "truecolor",
+			},
+			isPTY:       true,
+			wantedLevel: Level16M,
+		},
+		"term takes precedence over the environment": {
+			term: "xterm-256color",
+			env: map[string]string{
+				"TERM": "dumb",
+			},
+			isPTY:       true,
+			wantedLevel: Level256,
+		},
+		"TERM from the environment": {
+			env: map[string]string{
+				"TERM": "dumb",
+			},
+			isPTY:       true,
+			wantedLevel: LevelNone,
+		},
+		"without a pty": {
+			term:        "xterm-256color",
+			wantedLevel: LevelNone,
+		},
+		"without a pty forced by the client": {
+			env: map[string]string{
+				"FORCE_COLOR": "3",
+			},
+			wantedLevel: Level16M,
+		},
+		"disabled by the client": {
+			term: "xterm-256color",
+			env: map[string]string{
+				"NO_COLOR": "1",
+			},
+			isPTY:       true,
+			wantedLevel: LevelNone,
+		},
+		"ignores the terminfo directories of the client": {
+			term: "termcolor-direct",
+			env: map[string]string{
+				"TERMINFO": "testdata/terminfo",
+			},
+			isPTY:       true,
+			wantedLevel: LevelBasic,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			l := SessionLevel(tc.term, tc.env, tc.isPTY)
+
+			// Then
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestNewSessionDetector(t *testing.T) {
+	// Given
+	env := map[string]string{"TERM": "dumb"}
+	d := NewSessionDetector("termcolor-direct", env, true, WithTerminfoDirs("testdata/terminfo"))
+
+	// When
+	l := d.Level(os.Stderr)
+
+	// Then
+	if l != Level16M {
+		t.Errorf("expected %v, got %v", Level16M, l)
+	}
+	if env["TERM"] != "dumb" {
+		t.Errorf("expected the environment of the client to be left as is, got TERM=%q", env["TERM"])
+	}
+}
+
+func TestSessionLevel_IgnoresCurrentProcess(t *testing.T) {
+	// Given
+	args = []string{"cli", "--color=16m"}
+	defer func() { args = os.Args }()
+
+	// When
+	l := SessionLevel("dumb", nil, false)
+
+	// Then
+	if l != LevelNone {
+		t.Errorf("expected %v, got %v", LevelNone, l)
+	}
+}
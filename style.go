@@ -0,0 +1,92 @@
+package termcolor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Style is a set of colors and text attributes that can be rendered at any color level.
+// The zero value renders text as is.
+type Style struct {
+	// Foreground and Background are the colors of the text and of the cells behind it.
+	// A nil color keeps the terminal's default.
+	Foreground Color
+	Background Color
+
+	Bold          bool
+	Dim           bool
+	Italic        bool
+	Underline     bool
+	Inverse       bool
+	Strikethrough bool
+}
+
+// sgrAttribute is a text attribute along with the SGR parameters that turn it on and off.
+type sgrAttribute struct {
+	on, off string
+	enabled func(s Style) bool
+}
+
+var sgrAttributes = []sgrAttribute{
+	{on: "1", off: "22", enabled: func(s Style) bool { return s.Bold }},
+	{on: "2", off: "22", enabled: func(s Style) bool { return s.Dim }},
+	{on: "3", off: "23", enabled: func(s Style) bool { return s.Italic }},
+	{on: "4", off: "24", enabled: func(s Style) bool { return s.Underline }},
+	{on: "7", off: "27", enabled: func(s Style) bool { return s.Inverse }},
+	{on: "9", off: "29", enabled: func(s Style) bool { return s.Strikethrough }},
+}
+
+// Render wraps str with the escape sequences of the style that the level supports.
+// Colors are downsampled to the closest color available at the level. At LevelNone, str is returned as is.
+// To render bright colors with the normal ones on terminals with 8 colors, pass ColorsLevel(Colors(f)) rather than
+// SupportLevel(f).
+//
+// The style is closed with sequences that only turn off what it turned on, such as 39 for the foreground color,
+// rather than with a full reset. When a rendered string is embedded in text rendered with another style, the
+// attributes that only the outer style sets are kept after the string, but the ones that both set, such as the
+// foreground color, are reset to the terminal's defaults rather than to the outer style's.
+func (s Style) Render(str string, l Level) string {
+	if l == LevelNone {
+		return str
+	}
+	start, end := s.sequences(l)
+	if start == "" {
+		return str
+	}
+	return start + str + end
+}
+
+// Sprintf formats according to a format specifier and renders the result with the style at the level.
+func (s Style) Sprintf(l Level, format string, a ...interface{}) string {
+	return s.Render(fmt.Sprintf(format, a...), l)
+}
+
+// sequences returns the escape sequences that turn the style on and off at the level.
+func (s Style) sequences(l Level) (start, end string) {
+	var on, off []string
+	for _, attr := range sgrAttributes {
+		if !attr.enabled(s) {
+			continue
+		}
+		on = append(on, attr.on)
+		if len(off) == 0 || off[len(off)-1] != attr.off {
+			off = append(off, attr.off)
+		}
+	}
+	if s.Foreground != nil {
+		if p := s.Foreground.sgr(l, false); p != "" {
+			on = append(on, p)
+			off = append(off, "39")
+		}
+	}
+	if s.Background != nil {
+		if p := s.Background.sgr(l, true); p != "" {
+			on = append(on, p)
+			off = append(off, "49")
+		}
+	}
+	if len(on) == 0 {
+		return "", ""
+	}
+	return "\x1b[" + strings.Join(on, ";") + "m", "\x1b[" + strings.Join(off, ";") + "m"
+}
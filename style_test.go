@@ -0,0 +1,119 @@
+package termcolor
+
+import "testing"
+
+func TestStyle_Render(t *testing.T) {
+	testCases := map[string]struct {
+		style Style
+		level Level
+
+		wanted string
+	}{
+		"zero style": {
+			style:  Style{},
+			level:  Level16M,
+			wanted: "Success!",
+		},
+		"no colors": {
+			style:  Style{Foreground: RGB{25, 255, 203}, Bold: true},
+			level:  LevelNone,
+			wanted: "Success!",
+		},
+		"true color foreground": {
+			style:  Style{Foreground: RGB{25, 255, 203}},
+			level:  Level16M,
+			wanted: "\x1b[38;2;25;255;203mSuccess!\x1b[39m",
+		},
+		"true color downsampled to 256 colors": {
+			style:  Style{Foreground: RGB{250, 5, 0}},
+			level:  Level256,
+			wanted: "\x1b[38;5;196mSuccess!\x1b[39m",
+		},
+		"true color downsampled to basic colors": {
+			style:  Style{Foreground: RGB{250, 5, 0}},
+			level:  LevelBasic,
+			wanted: "\x1b[91mSuccess!\x1b[39m",
+		},
+		"256 color background": {
+			style:  Style{Background: Color256(118)},
+			level:  Level16M,
+			wanted: "\x1b[48;5;118mSuccess!\x1b[49m",
+		},
+		"256 color downsampled to basic colors": {
+			style:  Style{Background: Color256(46)},
+			level:  LevelBasic,
+			wanted: "\x1b[102mSuccess!\x1b[49m",
+		},
+		"256 color in the basic range": {
+			style:  Style{Foreground: Color256(9)},
+			level:  LevelBasic,
+			wanted: "\x1b[91mSuccess!\x1b[39m",
+		},
+		"basic colors": {
+			style:  Style{Foreground: Red, Background: BrightWhite},
+			level:  LevelBasic,
+			wanted: "\x1b[31;107mSuccess!\x1b[39;49m",
+		},
+		"attributes": {
+			style: Style{
+				Bold:          true,
+				Dim:           true,
+				Italic:        true,
+				Underline:     true,
+				Inverse:       true,
+				Strikethrough: true,
+			},
+			level:  LevelBasic,
+			wanted: "\x1b[1;2;3;4;7;9mSuccess!\x1b[22;23;24;27;29m",
+		},
+		"attributes with colors": {
+			style:  Style{Foreground: Green, Bold: true},
+			level:  Level256,
+			wanted: "\x1b[1;32mSuccess!\x1b[22;39m",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			s := tc.style.Render("Success!", tc.level)
+
+			// Then
+			if s != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, s)
+			}
+		})
+	}
+}
+
+func TestStyle_Sprintf(t *testing.T) {
+	// Given
+	style := Style{Foreground: BrightGreen}
+
+	// When
+	s := style.Sprintf(LevelBasic, "%d tests passed", 3)
+
+	// Then
+	if wanted := "\x1b[92m3 tests passed\x1b[39m"; s != wanted {
+		t.Errorf("expected %q, got %q", wanted, s)
+	}
+}
+
+func TestColor256_RGB(t *testing.T) {
+	testCases := map[Color256]RGB{
+		1:   {205, 0, 0},
+		16:  {0, 0, 0},
+		118: {135, 255, 0},
+		231: {255, 255, 255},
+		232: {8, 8, 8},
+		255: {238, 238, 238},
+	}
+	for c, wanted := range testCases {
+		if got := c.RGB(); got != wanted {
+			t.Errorf("expected color %d to be %v, got %v", c, wanted, got)
+		}
+	}
+}
@@ -2,11 +2,6 @@
 package termcolor
 
 import (
-	"os"
-	"regexp"
-	"strconv"
-	"strings"
-
 	"github.com/mattn/go-isatty"
 )
 
@@ -19,6 +14,8 @@ type FileDescriptor interface {
 type Level int
 
 // Color levels that can be supported by a terminal.
+// Level8 and Level88 come last to keep the values of the other levels, so levels that include them must be
+// compared with AtLeast rather than with the < and > operators.
 // See https://en.wikipedia.org/wiki/ANSI_escape_code#Colors
 const (
 	// LevelNone represents a terminal that does not support colors.
@@ -29,229 +26,144 @@ const (
 	Level256
 	// Level16M represents a terminal that can support "true colors".
 	Level16M
+	// Level8 represents a terminal that can only support the 8 basic colors, such as the Linux console.
+	// Bright colors are rendered with their normal counterparts.
+	Level8
+	// Level88 represents a terminal that can support the 88 colors of rxvt's palette.
+	Level88
 )
 
-// Supports16M returns true if the file descriptor can support true colors.
-func Supports16M(f FileDescriptor) bool {
-	return SupportLevel(f) == Level16M
-}
-
-// Supports256 returns true if the file descriptor can support 256 colors.
-func Supports256(f FileDescriptor) bool {
-	return SupportLevel(f) >= Level256
-}
-
-// SupportsBasic returns true if the file descriptor can support the basic 16 colors.
-func SupportsBasic(f FileDescriptor) bool {
-	return SupportLevel(f) >= LevelBasic
-}
-
-// SupportNone returns true if the file descriptor cannot support colors.
-func SupportsNone(f FileDescriptor) bool {
-	return SupportLevel(f) >= LevelNone
-}
-
-// SupportLevel returns the color level that's supported by the file descriptor.
-// If the environment variables set no color, then returns LevelNone.
-func SupportLevel(f FileDescriptor) Level {
-	// Flags take priority over anything else.
-	if hasDisabledFlag() {
-		return LevelNone
-	}
-	if has16MFlag() {
-		return Level16M
-	}
-	if has256Flag() {
-		return Level256
+// Colors returns the number of colors available at the level.
+func (l Level) Colors() int {
+	switch l {
+	case Level8:
+		return 8
+	case LevelBasic:
+		return 16
+	case Level88:
+		return 88
+	case Level256:
+		return 256
+	case Level16M:
+		return 1 << 24
+	default:
+		return 0
 	}
+}
 
-	if !isTerminal(f.Fd())  {
-		// If the user forces colors proceed even though it's not a terminal.
-		if _, ok := os.LookupEnv("FORCE_COLOR"); !ok {
-			return LevelNone
+// ColorsLevel returns the level that renders n colors, such as Level8 for 8 colors, or the level with the most
+// colors below n. It's meant to render with the number of colors returned by Colors:
+//
+//	termcolor.Style{Foreground: termcolor.BrightRed}.Render("error", termcolor.ColorsLevel(termcolor.Colors(os.Stderr)))
+func ColorsLevel(n int) Level {
+	for _, l := range []Level{Level16M, Level256, Level88, LevelBasic, Level8} {
+		if n >= l.Colors() {
+			return l
 		}
 	}
+	return LevelNone
+}
 
-	min := minLevel()
-	// Retrieve color from environment variables.
-	if isDumbTerminal() {
-		return min
-	}
-	if l, isWindows := lookupWindows(); isWindows {
-		return l
-	}
-	if l, isCI := lookupCI(min); isCI {
-		return l
-	}
-	if isTrueColorTerminal() {
-		return Level16M
-	}
-	if l, isMacOS := lookupMacOS(); isMacOS {
-		return l
-	}
-	if is256Terminal() {
-		return Level256
-	}
-	if isBasicTerminal() {
+// standard returns the level among LevelNone, LevelBasic, Level256 and Level16M that Detector.Level reports for l.
+func (l Level) standard() Level {
+	if l == Level8 || l == Level88 {
 		return LevelBasic
 	}
-	return min
+	return l
 }
 
-
-// Point to dependencies for testing.
-var isTerminal = isatty.IsTerminal
-
-func hasDisabledFlag() bool {
-	if hasFlag("no-color") {
-		return true
-	}
-	if hasFlag("no-colors") {
-		return true
-	}
-	if hasFlag("color=false") {
-		return true
-	}
-	return hasFlag("color=never")
+// AtLeast returns true if the level has at least as many colors as other.
+func (l Level) AtLeast(other Level) bool {
+	return l.Colors() >= other.Colors()
 }
 
-func has16MFlag() bool {
-	if hasFlag("color=16m") {
-		return true
+// Convert returns the color closest to c that can be rendered at the level.
+// Colors that are available at the level are returned as is, and nil is returned at LevelNone.
+func (l Level) Convert(c Color) Color {
+	if l == LevelNone {
+		return nil
 	}
-	if hasFlag("color=full") {
-		return true
+	switch c := c.(type) {
+	case RGB:
+		switch {
+		case !l.AtLeast(LevelBasic):
+			return nearest8(c)
+		case !l.AtLeast(Level88):
+			return NearestBasic(c)
+		case !l.AtLeast(Level256):
+			return Nearest88(c)
+		case !l.AtLeast(Level16M):
+			return Nearest256(c)
+		}
+	case Color256:
+		if !l.AtLeast(Level256) {
+			if c < 16 {
+				return l.Convert(BasicColor(c))
+			}
+			return l.Convert(c.RGB())
+		}
+	case Color88:
+		switch {
+		case c < 16:
+			return l.Convert(BasicColor(c))
+		case l == Level88:
+			return c
+		default:
+			// The colors of the 88 colors palette aren't in the other palettes.
+			return l.Convert(c.RGB())
+		}
+	case BasicColor:
+		if !l.AtLeast(LevelBasic) && c&15 >= BrightBlack {
+			return c&15 - BrightBlack
+		}
 	}
-	return hasFlag("color=truecolor")
+	return c
 }
 
-func has256Flag() bool {
-	return hasFlag("color=256")
+// Supports16M returns true if the file descriptor can support true colors.
+func Supports16M(f FileDescriptor) bool {
+	return SupportLevel(f) == Level16M
 }
 
-func minLevel() Level {
-	if _, ok := os.LookupEnv("FORCE_COLOR"); ok {
-		return forceColorValue()
-	}
-	if hasFlag("color") {
-		return LevelBasic
-	}
-	if hasFlag("colors") {
-		return LevelBasic
-	}
-	if hasFlag("color=true") {
-		return LevelBasic
-	}
-	if hasFlag("color=always") {
-		return LevelBasic
-	}
-	return LevelNone
+// Supports256 returns true if the file descriptor can support 256 colors.
+func Supports256(f FileDescriptor) bool {
+	return SupportLevel(f).AtLeast(Level256)
 }
 
-func forceColorValue() Level {
-	fc := os.Getenv("FORCE_COLOR")
-	if fc == "true" {
-		return LevelBasic
-	}
-	if fc == "false" {
-		return LevelNone
-	}
-	num, err := strconv.Atoi(fc)
-	if err != nil {
-		// If not a number then return basic colors.
-		return LevelBasic
-	}
-	switch l := Level(num); l {
-	case LevelNone:
-		return LevelNone
-	case Level256:
-		return Level256
-	case Level16M:
-		return Level16M
-	default:
-		// If the number is out of bounds default to basic.
-		return LevelBasic
-	}
+// Supports88 returns true if the file descriptor can support at least 88 colors.
+func Supports88(f FileDescriptor) bool {
+	return Colors(f) >= Level88.Colors()
 }
 
-func isDumbTerminal() bool {
-	return os.Getenv("TERM") == "dumb"
+// SupportsBasic returns true if the file descriptor can support the basic 16 colors.
+func SupportsBasic(f FileDescriptor) bool {
+	return SupportLevel(f).AtLeast(LevelBasic)
 }
 
-func isTrueColorTerminal() bool {
-	return os.Getenv("COLORTERM") == "truecolor"
+// Supports8 returns true if the file descriptor can support at least the 8 basic colors without their bright
+// variants.
+func Supports8(f FileDescriptor) bool {
+	return Colors(f) >= Level8.Colors()
 }
 
-// colored256Screen matches terminals containing "-256" or "-256color".
-var colored256Screen = regexp.MustCompile(`-256(color)`)
-
-func is256Terminal() bool {
-	return colored256Screen.MatchString(os.Getenv("TERM"))
+// SupportNone returns true if the file descriptor cannot support colors.
+func SupportsNone(f FileDescriptor) bool {
+	return SupportLevel(f).AtLeast(LevelNone)
 }
 
-// coloredScreen matches other well known basic colored terminals.
-var coloredScreen = regexp.MustCompile(`^screen|^xterm|^vt100|^vt220|^rxvt|color|ansi|cygwin|linux`)
-
-func isBasicTerminal() bool {
-	if coloredScreen.MatchString(os.Getenv("TERM")) {
-		return true
-	}
-	_, isColored := os.LookupEnv("COLORTERM")
-	return isColored
+// SupportLevel returns the color level that's supported by the file descriptor.
+// If the environment variables set no color, then returns LevelNone.
+// The level is one of LevelNone, LevelBasic, Level256 or Level16M, see Colors for terminals with 8 or 88 colors.
+func SupportLevel(f FileDescriptor) Level {
+	return NewDetector().Level(f)
 }
 
-// teamCityVersion matches if the version is greater than 9.1.0.
-var teamCityVersion = regexp.MustCompile(`^(9\.(0*[1-9]\d*)\.|\d{2,}\.)`)
-
-func lookupCI(min Level) (Level, bool) {
-	if _, ok := os.LookupEnv("TEAMCITY_VERSION"); ok {
-		if teamCityVersion.MatchString(os.Getenv("TEAMCITY_VERSION")) {
-			return LevelBasic, true
-		}
-		return LevelNone, true
-	}
-	if _, ok := os.LookupEnv("GITHUB_ACTIONS"); ok {
-		return LevelBasic, true
-	}
-
-	// Other CI products set the env CI=true.
-	if _, ok := os.LookupEnv("CI"); !ok {
-		return LevelNone, false
-	}
-	if _, ok := os.LookupEnv("TRAVIS"); ok {
-		return LevelBasic, true
-	}
-	if _, ok := os.LookupEnv("CIRCLECI"); ok {
-		return LevelBasic, true
-	}
-	if _, ok := os.LookupEnv("APPVEYOR"); ok {
-		return LevelBasic, true
-	}
-	if _, ok := os.LookupEnv("GITLAB_CI"); ok {
-		return LevelBasic, true
-	}
-	if os.Getenv("CI_NAME") == "codeship" {
-		return LevelBasic, true
-	}
-	return min, true
+// Colors returns the number of colors that the file descriptor supports, or 0 if it doesn't support colors.
+// Unlike SupportLevel, it tells terminals with 8 colors, such as the Linux console, and 88 colors apart from the ones
+// with the basic 16 colors.
+func Colors(f FileDescriptor) int {
+	return NewDetector().Colors(f)
 }
 
-func lookupMacOS() (Level, bool) {
-	prog, isMacOS := os.LookupEnv("TERM_PROGRAM")
-	if !isMacOS {
-		return LevelNone, false
-	}
-	switch prog {
-	case "iTerm.app":
-		// Default is 0 if can't convert to integer.
-		v, _ := strconv.Atoi(strings.Split(os.Getenv("TERM_PROGRAM_VERSION"), ".")[0])
-		if v >= 3 {
-			return Level16M, true
-		}
-		return Level256, true
-	case "Apple_Terminal":
-		return Level256, true
-	default:
-		return LevelNone, false
-	}
-}
\ No newline at end of file
+// Point to dependencies for testing.
+var isTerminal = isatty.IsTerminal
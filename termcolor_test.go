@@ -160,7 +160,7 @@ func TestSupportLevel(t *testing.T) {
 				"GITHUB_ACTIONS": "true",
 			},
 			isTerminal: true,
-			wantedLevel: LevelBasic,
+			wantedLevel: Level16M,
 		},
 		"with COLORTERM set to truecolor": {
 			envs: map[string]string {
@@ -0,0 +1,78 @@
+package termcolortest
+
+import "github.com/efekarakus/termcolor"
+
+// Fixtures returns all the simulated environments of the package.
+func Fixtures() []Environment {
+	return []Environment{
+		ITerm3OverSSH(),
+		GitHubActions(),
+		TmuxInAlacritty(),
+		DumbPipe(),
+	}
+}
+
+// ITerm3OverSSH returns a shell on a remote host opened from iTerm2. ssh forwards TERM and the locale, but not
+// TERM_PROGRAM, so the level is derived from TERM.
+func ITerm3OverSSH() Environment {
+	return Environment{
+		Name: "iTerm 3 over ssh",
+		Env: map[string]string{
+			"TERM":                "xterm-256color",
+			"LANG":                "en_US.UTF-8",
+			"LC_TERMINAL":         "iTerm2",
+			"LC_TERMINAL_VERSION": "3.4.19",
+			"SSH_CONNECTION":      "203.0.113.5 52144 198.51.100.7 22",
+			"SSH_TTY":             "/dev/pts/0",
+		},
+		Args:     []string{"cli"},
+		Terminal: true,
+		Level:    termcolor.Level256,
+	}
+}
+
+// GitHubActions returns a step of a GitHub Actions workflow, whose output is a pipe to the log viewer.
+func GitHubActions() Environment {
+	return Environment{
+		Name: "GitHub Actions",
+		Env: map[string]string{
+			"CI":             "true",
+			"GITHUB_ACTIONS": "true",
+			"RUNNER_OS":      "Linux",
+		},
+		Args:  []string{"cli"},
+		Level: termcolor.LevelNone,
+	}
+}
+
+// TmuxInAlacritty returns a tmux pane in Alacritty, where tmux is configured to pass true colors through.
+func TmuxInAlacritty() Environment {
+	return Environment{
+		Name: "tmux in Alacritty",
+		Env: map[string]string{
+			"TERM":             "tmux-256color",
+			"TMUX":             "/tmp/tmux-1000/default,1234,0",
+			"TMUX_PANE":        "%0",
+			"ALACRITTY_SOCKET": "/run/user/1000/Alacritty-:0-1234.sock",
+			"LANG":             "en_US.UTF-8",
+		},
+		Args:     []string{"cli"},
+		Terminal: true,
+		Commands: map[string]string{
+			"tmux show-options -s": "terminal-features[0] xterm*:clipboard:ccolour:cstyle:focus:title\n" +
+				"terminal-features[1] alacritty:RGB\n",
+			"tmux display-message -p #{client_termname}": "alacritty\n",
+		},
+		Level: termcolor.Level16M,
+	}
+}
+
+// DumbPipe returns the output of a program piped to another one from a terminal without capabilities.
+func DumbPipe() Environment {
+	return Environment{
+		Name:  "dumb pipe",
+		Env:   map[string]string{"TERM": "dumb"},
+		Args:  []string{"cli"},
+		Level: termcolor.LevelNone,
+	}
+}
@@ -0,0 +1,134 @@
+// Package termcolortest provides simulated terminal environments to test code that depends on color support,
+// without mutating the environment, arguments or terminal state of the test process.
+package termcolortest
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/efekarakus/termcolor"
+	"github.com/efekarakus/termcolor/ansi"
+)
+
+// Environment is a simulated terminal environment.
+type Environment struct {
+	// Name describes the environment, such as "tmux in Alacritty".
+	Name string
+	// Env holds the environment variables.
+	Env map[string]string
+	// Args are the command line arguments, starting with the program name.
+	Args []string
+	// Terminal is true if the file descriptors are terminals.
+	Terminal bool
+	// Commands maps command lines, such as "tmux show-options -s", to their output.
+	// Commands that aren't listed fail.
+	Commands map[string]string
+	// Level is the color level that termcolor detects in the environment.
+	Level termcolor.Level
+}
+
+// File is a file descriptor that can be passed to detectors. Simulated environments don't look at its value.
+type File uintptr
+
+// Fd returns the file descriptor.
+func (f File) Fd() uintptr {
+	return uintptr(f)
+}
+
+// Simulated standard streams.
+const (
+	Stdout File = 1
+	Stderr File = 2
+)
+
+// Options returns the options that make a detector read the environment instead of the process.
+// Terminfo entries aren't read from the system, so the level only depends on the environment.
+func (e Environment) Options() []termcolor.DetectorOption {
+	return []termcolor.DetectorOption{
+		termcolor.WithArgs(e.Args),
+		termcolor.WithEnv(e.Env),
+		termcolor.WithTerminalChecker(func(fd uintptr) bool {
+			return e.Terminal
+		}),
+		termcolor.WithTerminfoDirs(),
+		termcolor.WithCommandRunner(e.run),
+	}
+}
+
+// Detector returns a detector that reads the environment. The options are applied after the environment's.
+func (e Environment) Detector(opts ...termcolor.DetectorOption) *termcolor.Detector {
+	return termcolor.NewDetector(append(e.Options(), opts...)...)
+}
+
+func (e Environment) run(name string, args ...string) ([]byte, error) {
+	line := strings.Join(append([]string{name}, args...), " ")
+	out, ok := e.Commands[line]
+	if !ok {
+		return nil, fmt.Errorf("termcolortest: command %q not found in %q", line, e.Name)
+	}
+	return []byte(out), nil
+}
+
+// Setenv sets the environment's variables in the process for code that calls termcolor.SupportLevel directly, and
+// returns a function that restores their previous values:
+//
+//	defer termcolortest.TmuxInAlacritty().Setenv(t)()
+//
+// The other variables of the process are left as is, so variables such as COLORTERM or CI that are set outside of
+// the test still apply. Prefer Detector, which doesn't touch the process. Tests that call Setenv must not run in
+// parallel with other tests.
+func (e Environment) Setenv(t testing.TB) (restore func()) {
+	t.Helper()
+	type saved struct {
+		value string
+		ok    bool
+	}
+	previous := make(map[string]saved, len(e.Env))
+	restore = func() {
+		for k, s := range previous {
+			if s.ok {
+				os.Setenv(k, s.value)
+			} else {
+				os.Unsetenv(k)
+			}
+		}
+	}
+	for k, v := range e.Env {
+		old, ok := os.LookupEnv(k)
+		previous[k] = saved{value: old, ok: ok}
+		if err := os.Setenv(k, v); err != nil {
+			restore()
+			t.Fatalf("termcolortest: set %s: %v", k, err)
+		}
+	}
+	return restore
+}
+
+// AssertLevel fails the test if the detector's level for f isn't wanted, and reports the rules that decided it.
+func AssertLevel(t testing.TB, d *termcolor.Detector, f termcolor.FileDescriptor, wanted termcolor.Level) {
+	t.Helper()
+	if decision := d.Explain(f); decision.Level != wanted {
+		t.Errorf("expected level %v, got %s", wanted, decision)
+	}
+}
+
+// AssertRendered fails the test if got isn't wanted, and reports both strings with their escape sequences quoted.
+func AssertRendered(t testing.TB, got, wanted string) {
+	t.Helper()
+	if got != wanted {
+		t.Errorf("expected %q, got %q", wanted, got)
+	}
+}
+
+// AssertPlain fails the test if s contains escape sequences, such as colors written to a pipe.
+func AssertPlain(t testing.TB, s string) {
+	t.Helper()
+	for _, token := range ansi.Tokenize([]byte(s)) {
+		if token.Kind != ansi.Text && token.Kind != ansi.Control {
+			t.Errorf("expected plain text, got %v in %q", token.Kind, s)
+			return
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package termcolortest
+
+import (
+	"os"
+	"testing"
+
+	"github.com/efekarakus/termcolor"
+)
+
+func TestFixtures(t *testing.T) {
+	for _, env := range Fixtures() {
+		env := env
+		t.Run(env.Name, func(t *testing.T) {
+			t.Parallel()
+
+			AssertLevel(t, env.Detector(), Stdout, env.Level)
+		})
+	}
+}
+
+func TestEnvironment_Detector(t *testing.T) {
+	// Given
+	env := TmuxInAlacritty()
+
+	// When
+	d := env.Detector(termcolor.WithColorPolicy(termcolor.NoColorPolicy))
+
+	// Then
+	if m := d.Multiplexer(); m != termcolor.Tmux {
+		t.Errorf("expected %v, got %v", termcolor.Tmux, m)
+	}
+	AssertRendered(t, termcolor.Style{Foreground: termcolor.RGB{R: 255}}.Render("x", d.Level(Stdout)), "\x1b[38;2;255;0;0mx\x1b[39m")
+	AssertPlain(t, termcolor.Style{Bold: true}.Render("x", DumbPipe().Detector().Level(Stdout)))
+}
+
+func TestEnvironment_Setenv(t *testing.T) {
+	// Given
+	env := Environment{
+		Name: "sentinels",
+		Env: map[string]string{
+			"TERMCOLORTEST_SET":   "new",
+			"TERMCOLORTEST_UNSET": "new",
+		},
+	}
+	os.Setenv("TERMCOLORTEST_SET", "old")
+	os.Setenv("TERMCOLORTEST_OTHER", "other")
+	os.Unsetenv("TERMCOLORTEST_UNSET")
+	defer os.Unsetenv("TERMCOLORTEST_SET")
+	defer os.Unsetenv("TERMCOLORTEST_OTHER")
+
+	// When
+	restore := env.Setenv(t)
+	set, unset, other := os.Getenv("TERMCOLORTEST_SET"), os.Getenv("TERMCOLORTEST_UNSET"), os.Getenv("TERMCOLORTEST_OTHER")
+	restore()
+
+	// Then
+	if set != "new" || unset != "new" {
+		t.Errorf("expected the environment's variables to be set, got %q and %q", set, unset)
+	}
+	if other != "other" {
+		t.Errorf("expected the other variables of the process to be left as is, got %q", other)
+	}
+	if v := os.Getenv("TERMCOLORTEST_SET"); v != "old" {
+		t.Errorf("expected the previous value to be restored, got %q", v)
+	}
+	if _, ok := os.LookupEnv("TERMCOLORTEST_UNSET"); ok {
+		t.Errorf("expected the variable that wasn't set to be unset")
+	}
+}
+
+func TestAssertPlain(t *testing.T) {
+	// Given
+	ft := &fakeT{}
+
+	// When
+	AssertPlain(ft, "ok\n\x1b[1mbold\x1b[22m")
+
+	// Then
+	if !ft.failed {
+		t.Errorf("expected AssertPlain to fail")
+	}
+}
+
+// fakeT records failures instead of failing the test.
+type fakeT struct {
+	testing.TB
+	failed bool
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.failed = true
+}
@@ -0,0 +1,240 @@
+package termcolor
+
+import (
+	"fmt"
+)
+
+// Terminal describes a terminal emulator and the color levels of its versions.
+type Terminal struct {
+	// Name identifies the terminal, such as "WezTerm".
+	Name string
+	// Env lists the environment variables that are all set by the terminal.
+	// An entry of the form "NAME=value" also requires the variable to have that exact value.
+	Env []string
+	// VersionEnv is the environment variable that holds the version of the terminal, checked against the
+	// constraints of Versions.
+	VersionEnv string
+	// Versions maps version constraints to color levels and features. The first constraint that the version
+	// satisfies gives the level of the terminal and the features it supports on top of Features.
+	Versions []TerminalVersion
+	// Level is the color level of the terminal when its version is unknown or satisfies none of the constraints.
+	Level Level
+	// Features are the features supported by all the versions of the terminal.
+	// The features that aren't listed are reported as unsupported by Capabilities.
+	Features Feature
+}
+
+// TerminalVersion is the color level and features of the versions of a terminal that satisfy a constraint.
+type TerminalVersion struct {
+	// Constraint is a version constraint such as ">=3" or ">=0.50 <0.70".
+	// Requirements are separated by spaces or commas and alternatives by "||".
+	Constraint string
+	Level      Level
+	// Features are the features supported by the versions in addition to the ones of the terminal.
+	Features Feature
+
+	constraint constraint
+}
+
+// Features that most terminals emulating xterm support.
+const xtermFeatures = FeatureItalic | FeatureStrikethrough | FeatureBracketedPaste | FeatureBoxDrawing
+
+// allFeatures are the features of terminals that support them all.
+const allFeatures = xtermFeatures | FeatureCurlyUnderline | FeatureColoredUnderline | FeatureHyperlinks |
+	FeatureSynchronizedOutput
+
+// terminals holds the registered terminals, in the order they're checked.
+var terminals = mustTerminals(
+	Terminal{
+		Name:       "iTerm2",
+		Env:        []string{"TERM_PROGRAM=iTerm.app"},
+		VersionEnv: "TERM_PROGRAM_VERSION",
+		Versions: []TerminalVersion{
+			{Constraint: ">=3.1", Level: Level16M, Features: FeatureHyperlinks},
+			{Constraint: ">=3", Level: Level16M},
+		},
+		Level:    Level256,
+		Features: xtermFeatures,
+	},
+	Terminal{
+		Name:     "Terminal.app",
+		Env:      []string{"TERM_PROGRAM=Apple_Terminal"},
+		Level:    Level256,
+		Features: FeatureItalic | FeatureBracketedPaste | FeatureBoxDrawing,
+	},
+	Terminal{
+		Name:       "Visual Studio Code",
+		Env:        []string{"TERM_PROGRAM=vscode"},
+		VersionEnv: "TERM_PROGRAM_VERSION",
+		// The integrated terminal renders true colors since xterm.js 3.14, and hyperlinks since 1.72.
+		Versions: []TerminalVersion{
+			{Constraint: ">=1.72", Level: Level16M, Features: FeatureHyperlinks},
+			{Constraint: ">=1.36", Level: Level16M},
+		},
+		Level:    Level256,
+		Features: xtermFeatures,
+	},
+	Terminal{Name: "WezTerm", Env: []string{"TERM_PROGRAM=WezTerm"}, Level: Level16M, Features: allFeatures},
+	Terminal{Name: "WezTerm", Env: []string{"WEZTERM_EXECUTABLE"}, Level: Level16M, Features: allFeatures},
+	Terminal{Name: "Ghostty", Env: []string{"TERM_PROGRAM=ghostty"}, Level: Level16M, Features: allFeatures},
+	Terminal{
+		Name:       "Hyper",
+		Env:        []string{"TERM_PROGRAM=Hyper"},
+		VersionEnv: "TERM_PROGRAM_VERSION",
+		Versions:   []TerminalVersion{{Constraint: ">=2", Level: Level16M}},
+		Level:      Level256,
+		Features:   xtermFeatures,
+	},
+	Terminal{Name: "Tabby", Env: []string{"TERM_PROGRAM=Tabby"}, Level: Level16M, Features: xtermFeatures},
+	Terminal{
+		Name:       "mintty",
+		Env:        []string{"TERM_PROGRAM=mintty"},
+		VersionEnv: "TERM_PROGRAM_VERSION",
+		Versions:   []TerminalVersion{{Constraint: ">=2.0.1", Level: Level16M}},
+		Level:      Level256,
+		Features:   xtermFeatures | FeatureCurlyUnderline | FeatureColoredUnderline,
+	},
+	Terminal{Name: "kitty", Env: []string{"KITTY_WINDOW_ID"}, Level: Level16M, Features: allFeatures},
+	Terminal{
+		Name:     "Alacritty",
+		Env:      []string{"ALACRITTY_SOCKET"},
+		Level:    Level16M,
+		Features: xtermFeatures | FeatureCurlyUnderline | FeatureColoredUnderline | FeatureHyperlinks,
+	},
+	Terminal{
+		Name:     "Windows Terminal",
+		Env:      []string{"WT_SESSION"},
+		Level:    Level16M,
+		Features: xtermFeatures | FeatureHyperlinks,
+	},
+	Terminal{Name: "Konsole", Env: []string{"KONSOLE_VERSION"}, Level: Level16M, Features: xtermFeatures},
+	Terminal{Name: "Termux", Env: []string{"TERMUX_VERSION"}, Level: Level16M, Features: xtermFeatures},
+	Terminal{
+		Name:     "ConEmu",
+		Env:      []string{"ConEmuANSI=ON"},
+		Level:    Level16M,
+		Features: FeatureItalic | FeatureBoxDrawing,
+	},
+	Terminal{
+		Name:       "VTE",
+		Env:        []string{"VTE_VERSION"},
+		VersionEnv: "VTE_VERSION",
+		// VTE_VERSION is the version without dots, such as 6003 for 0.60.3.
+		// True colors are supported since 0.36, hyperlinks since 0.50, and styled underlines since 0.52.
+		Versions: []TerminalVersion{
+			{
+				Constraint: ">=5200",
+				Level:      Level16M,
+				Features:   FeatureHyperlinks | FeatureCurlyUnderline | FeatureColoredUnderline,
+			},
+			{Constraint: ">=5000", Level: Level16M, Features: FeatureHyperlinks},
+			{Constraint: ">=3600", Level: Level16M},
+		},
+		Level:    Level256,
+		Features: xtermFeatures,
+	},
+)
+
+// RegisterTerminal adds a terminal to the ones recognized by detectors.
+// Registered terminals are checked before the built-in ones, the last registered first, so they can override them.
+// RegisterTerminal is meant to be called from init functions: it isn't safe to call it concurrently with
+// detection. It panics if the terminal has no name or environment variables, or if a constraint is invalid.
+func RegisterTerminal(t Terminal) {
+	t = mustTerminal(t)
+	terminals = append([]Terminal{t}, terminals...)
+}
+
+// Terminals returns the registered terminals in the order they're checked.
+func Terminals() []Terminal {
+	return append([]Terminal(nil), terminals...)
+}
+
+func mustTerminals(ts ...Terminal) []Terminal {
+	for i, t := range ts {
+		ts[i] = mustTerminal(t)
+	}
+	return ts
+}
+
+func mustTerminal(t Terminal) Terminal {
+	if t.Name == "" {
+		panic("termcolor: terminal has no name")
+	}
+	if len(t.Env) == 0 {
+		panic(fmt.Sprintf("termcolor: terminal %q has no environment variables", t.Name))
+	}
+	versions := make([]TerminalVersion, len(t.Versions))
+	for i, v := range t.Versions {
+		c, err := parseConstraint(v.Constraint)
+		if err != nil {
+			panic(fmt.Sprintf("termcolor: terminal %q: %v", t.Name, err))
+		}
+		v.constraint = c
+		versions[i] = v
+	}
+	t.Versions = versions
+	return t
+}
+
+// Terminal returns the terminal emulator that the detector's environment runs in.
+// If no registered terminal matches, then returns false.
+func (d *Detector) Terminal() (Terminal, bool) {
+	e := detection{d: d}
+	return findTerminal(e.lookupEnv)
+}
+
+// findTerminal returns the first registered terminal whose environment variables are set.
+func findTerminal(lookupEnv func(key string) (string, bool)) (Terminal, bool) {
+	for _, t := range terminals {
+		if envMatches(t.Env, lookupEnv) {
+			return t, true
+		}
+	}
+	return Terminal{}, false
+}
+
+// level returns the color level of the terminal given the version found in its environment.
+func (t Terminal) level(lookupEnv func(key string) (string, bool)) Level {
+	if tv, ok := t.version(lookupEnv); ok {
+		return tv.Level
+	}
+	return t.Level
+}
+
+// features returns the features supported by the terminal given the version found in its environment.
+func (t Terminal) features(lookupEnv func(key string) (string, bool)) Feature {
+	if tv, ok := t.version(lookupEnv); ok {
+		return t.Features | tv.Features
+	}
+	return t.Features
+}
+
+// version returns the first entry of Versions whose constraint is satisfied by the version found in the
+// environment. If the version is unknown or satisfies none of the constraints, then returns false.
+func (t Terminal) version(lookupEnv func(key string) (string, bool)) (TerminalVersion, bool) {
+	if t.VersionEnv == "" {
+		return TerminalVersion{}, false
+	}
+	raw, _ := lookupEnv(t.VersionEnv)
+	v, ok := parseVersion(raw)
+	if !ok {
+		return TerminalVersion{}, false
+	}
+	for _, tv := range t.Versions {
+		if tv.constraint.check(v) {
+			return tv, true
+		}
+	}
+	return TerminalVersion{}, false
+}
+
+// lookupTerminal returns the color level of the terminal emulator.
+// If the terminal isn't registered, then returns false.
+func (e *detection) lookupTerminal() (Level, bool) {
+	lookupEnv := e.onceLookupEnv()
+	t, ok := findTerminal(lookupEnv)
+	if !ok {
+		return LevelNone, false
+	}
+	return t.level(lookupEnv), true
+}
@@ -0,0 +1,187 @@
+package termcolor
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDetector_Terminal(t *testing.T) {
+	testCases := map[string]struct {
+		envs map[string]string
+
+		wantedName  string
+		wantedOK    bool
+		wantedLevel Level
+	}{
+		"unknown terminal": {
+			envs:        map[string]string{"TERM_PROGRAM": "unknown"},
+			wantedLevel: LevelNone,
+		},
+		"iTerm 3": {
+			envs:        map[string]string{"TERM_PROGRAM": "iTerm.app", "TERM_PROGRAM_VERSION": "3.4.19"},
+			wantedName:  "iTerm2",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"iTerm without a version": {
+			envs:        map[string]string{"TERM_PROGRAM": "iTerm.app"},
+			wantedName:  "iTerm2",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+		"old vscode": {
+			envs:        map[string]string{"TERM_PROGRAM": "vscode", "TERM_PROGRAM_VERSION": "1.35.1"},
+			wantedName:  "Visual Studio Code",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+		"vscode": {
+			envs:        map[string]string{"TERM_PROGRAM": "vscode", "TERM_PROGRAM_VERSION": "1.85.0"},
+			wantedName:  "Visual Studio Code",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"wezterm without TERM_PROGRAM": {
+			envs:        map[string]string{"WEZTERM_EXECUTABLE": "/usr/bin/wezterm-gui"},
+			wantedName:  "WezTerm",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"kitty": {
+			envs:        map[string]string{"KITTY_WINDOW_ID": "1"},
+			wantedName:  "kitty",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"ghostty": {
+			envs:        map[string]string{"TERM_PROGRAM": "ghostty", "TERM_PROGRAM_VERSION": "1.0.0"},
+			wantedName:  "Ghostty",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"old mintty": {
+			envs:        map[string]string{"TERM_PROGRAM": "mintty", "TERM_PROGRAM_VERSION": "1.2"},
+			wantedName:  "mintty",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+		"old vte": {
+			envs:        map[string]string{"VTE_VERSION": "3405"},
+			wantedName:  "VTE",
+			wantedOK:    true,
+			wantedLevel: Level256,
+		},
+		"vte": {
+			envs:        map[string]string{"VTE_VERSION": "6003"},
+			wantedName:  "VTE",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+		"conemu with ansi disabled": {
+			envs:        map[string]string{"ConEmuANSI": "OFF"},
+			wantedLevel: LevelNone,
+		},
+		"windows terminal": {
+			envs:        map[string]string{"WT_SESSION": "0c6f8c4e-4a1e-4a70-9f44-8a7ad3e0b8d4"},
+			wantedName:  "Windows Terminal",
+			wantedOK:    true,
+			wantedLevel: Level16M,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(tc.envs),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return true
+				}),
+				WithTerminfoDirs(),
+			)
+
+			// When
+			term, ok := d.Terminal()
+			l := d.Level(os.Stdout)
+
+			// Then
+			if ok != tc.wantedOK {
+				t.Fatalf("expected ok %v, got %v", tc.wantedOK, ok)
+			}
+			if term.Name != tc.wantedName {
+				t.Errorf("expected terminal %q, got %q", tc.wantedName, term.Name)
+			}
+			if l != tc.wantedLevel {
+				t.Errorf("expected level %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestRegisterTerminal(t *testing.T) {
+	// Given
+	saved := terminals
+	defer func() {
+		terminals = saved
+	}()
+	RegisterTerminal(Terminal{
+		Name:       "In-house terminal",
+		Env:        []string{"TERM_PROGRAM=inhouse"},
+		VersionEnv: "TERM_PROGRAM_VERSION",
+		Versions: []TerminalVersion{
+			{Constraint: "<2", Level: LevelBasic},
+			{Constraint: ">=2 <3", Level: Level256},
+		},
+		Level: Level16M,
+	})
+
+	testCases := map[string]struct {
+		version string
+		wanted  Level
+	}{
+		"first constraint":    {version: "1.9", wanted: LevelBasic},
+		"second constraint":   {version: "2.5", wanted: Level256},
+		"default level":       {version: "3.0", wanted: Level16M},
+		"unparsable versions": {version: "dev", wanted: Level16M},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := NewDetector(
+				WithArgs(nil),
+				WithEnv(map[string]string{"TERM_PROGRAM": "inhouse", "TERM_PROGRAM_VERSION": tc.version}),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return true
+				}),
+				WithTerminfoDirs(),
+			)
+
+			if l := d.Level(os.Stdout); l != tc.wanted {
+				t.Errorf("expected %v, got %v", tc.wanted, l)
+			}
+		})
+	}
+}
+
+func TestRegisterTerminal_Panics(t *testing.T) {
+	testCases := map[string]Terminal{
+		"no name":            {Env: []string{"X"}},
+		"no env":             {Name: "X"},
+		"invalid constraint": {Name: "X", Env: []string{"X"}, Versions: []TerminalVersion{{Constraint: "<"}}},
+	}
+
+	for name, term := range testCases {
+		term := term
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("expected a panic")
+				}
+			}()
+			mustTerminal(term)
+		})
+	}
+}
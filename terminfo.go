@@ -0,0 +1,424 @@
+package termcolor
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Magic numbers of compiled terminfo entries.
+// See https://invisible-island.net/ncurses/man/term.5.html
+const (
+	// terminfoMagic is the magic number of the legacy storage format with 16-bit numbers.
+	terminfoMagic = 0432
+	// terminfoMagic32 is the magic number of the extended storage format with 32-bit numbers.
+	terminfoMagic32 = 01036
+)
+
+// terminfoSystemDirs are the directories searched for terminfo entries after the user's directories.
+var terminfoSystemDirs = []string{
+	"/etc/terminfo",
+	"/lib/terminfo",
+	"/usr/share/terminfo",
+	"/usr/lib/terminfo",
+	"/usr/local/share/terminfo",
+	"/usr/share/misc/terminfo",
+	"/boot/system/data/terminfo",
+}
+
+// Indexes of the predefined capabilities used by termcolor in the order defined by term(5).
+var (
+	terminfoNumbers = map[string]int{
+		"colors": 13,
+		"pairs":  14,
+	}
+	terminfoStrings = map[string]int{
+		"smacs": 25,
+		"bold":  27,
+		"dim":   30,
+		"rev":   34,
+		"smul":  36,
+		"sgr0":  39,
+		"acsc":  146,
+		"op":    297,
+		"sitm":  311,
+		"ritm":  321,
+		"setaf": 359,
+		"setab": 360,
+	}
+)
+
+// terminfo is a parsed compiled terminfo entry.
+type terminfo struct {
+	// names are the names of the terminal, the last one is usually a description.
+	names   []string
+	numbers []int
+	strings []string
+
+	// extended capabilities indexed by name.
+	extBools   map[string]bool
+	extNumbers map[string]int
+	extStrings map[string]string
+}
+
+// number returns the value of a numeric capability, predefined or extended.
+func (ti *terminfo) number(name string) (int, bool) {
+	if i, ok := terminfoNumbers[name]; ok && i < len(ti.numbers) && ti.numbers[i] >= 0 {
+		return ti.numbers[i], true
+	}
+	v, ok := ti.extNumbers[name]
+	return v, ok
+}
+
+// flag returns true if the extended boolean capability is set.
+func (ti *terminfo) flag(name string) bool {
+	return ti.extBools[name]
+}
+
+// str returns the value of a string capability, predefined or extended.
+func (ti *terminfo) str(name string) (string, bool) {
+	if i, ok := terminfoStrings[name]; ok && i < len(ti.strings) && ti.strings[i] != "" {
+		return ti.strings[i], true
+	}
+	v, ok := ti.extStrings[name]
+	return v, ok
+}
+
+// level returns the color level advertised by the entry.
+// Entries with 8 colors, such as xterm's or screen's, are basic since their terminals render the bright colors too.
+// Direct color support is advertised by the extended "RGB" capability, or tmux's "Tc" boolean.
+func (ti *terminfo) level() Level {
+	if ti.flag("RGB") || ti.flag("Tc") {
+		return Level16M
+	}
+	if _, ok := ti.extNumbers["RGB"]; ok {
+		return Level16M
+	}
+	if _, ok := ti.extStrings["RGB"]; ok {
+		return Level16M
+	}
+	colors, _ := ti.number("colors")
+	switch {
+	case colors >= 1<<24:
+		return Level16M
+	case colors >= 256:
+		return Level256
+	case colors >= 88:
+		return Level88
+	case colors >= 8:
+		return LevelBasic
+	default:
+		return LevelNone
+	}
+}
+
+// support returns whether the entry advertises a feature.
+// Features that are advertised by extended capabilities are unknown if the entry doesn't have them, since most
+// entries predate the capabilities.
+func (ti *terminfo) support(f Feature) Support {
+	var names []string
+	switch f {
+	case FeatureItalic:
+		if _, ok := ti.str("sitm"); ok {
+			return Supported
+		}
+		return Unsupported
+	case FeatureStrikethrough:
+		names = []string{"smxx"}
+	case FeatureCurlyUnderline:
+		names = []string{"Smulx", "Su"}
+	case FeatureColoredUnderline:
+		names = []string{"Setulc", "Su"}
+	case FeatureSynchronizedOutput:
+		names = []string{"Sync"}
+	case FeatureBracketedPaste:
+		names = []string{"BE"}
+	}
+	for _, name := range names {
+		if _, ok := ti.str(name); ok || ti.flag(name) {
+			return Supported
+		}
+	}
+	return SupportUnknown
+}
+
+// colorCaps returns the capabilities used to determine the color level in the terminfo source format.
+func (ti *terminfo) colorCaps() string {
+	var caps []string
+	if colors, ok := ti.number("colors"); ok {
+		caps = append(caps, fmt.Sprintf("colors#%d", colors))
+	}
+	for _, name := range []string{"RGB", "Tc"} {
+		if ti.flag(name) {
+			caps = append(caps, name)
+		}
+		if n, ok := ti.extNumbers[name]; ok {
+			caps = append(caps, fmt.Sprintf("%s#%d", name, n))
+		}
+		if v, ok := ti.extStrings[name]; ok {
+			caps = append(caps, fmt.Sprintf("%s=%s", name, v))
+		}
+	}
+	if len(caps) == 0 {
+		return "no colors"
+	}
+	return strings.Join(caps, " ")
+}
+
+// errTerminfoNotFound is returned when no compiled entry exists for a terminal.
+var errTerminfoNotFound = errors.New("terminfo entry not found")
+
+// terminfoDirs returns the directories to search for terminfo entries in order of priority.
+// See https://invisible-island.net/ncurses/man/terminfo.5.html#h3-Fetching-Compiled-Descriptions
+func terminfoDirs(getenv func(string) string, systemDirs []string) []string {
+	var dirs []string
+	if dir := getenv("TERMINFO"); dir != "" {
+		dirs = append(dirs, dir)
+	}
+	if home := getenv("HOME"); home != "" {
+		dirs = append(dirs, filepath.Join(home, ".terminfo"))
+	}
+	if list := getenv("TERMINFO_DIRS"); list != "" {
+		for _, dir := range strings.Split(list, ":") {
+			if dir == "" {
+				// An empty entry stands for the system directories.
+				dirs = append(dirs, systemDirs...)
+				continue
+			}
+			dirs = append(dirs, dir)
+		}
+	}
+	return append(dirs, systemDirs...)
+}
+
+// findTerminfo returns the path of the compiled entry for term in the first directory that has one.
+// Entries are stored under a subdirectory named after the first character of the terminal name, or its
+// hexadecimal code on case-insensitive filesystems.
+func findTerminfo(term string, dirs []string) (string, error) {
+	if term == "" || strings.ContainsAny(term, `/\`) || term == "." || term == ".." {
+		return "", errTerminfoNotFound
+	}
+	for _, dir := range dirs {
+		for _, sub := range []string{term[:1], fmt.Sprintf("%02x", term[0])} {
+			path := filepath.Join(dir, sub, term)
+			if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+				return path, nil
+			}
+		}
+	}
+	return "", errTerminfoNotFound
+}
+
+// loadTerminfo reads and parses the compiled entry at path.
+func loadTerminfo(path string) (*terminfo, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTerminfo(data)
+}
+
+// terminfoReader decodes the little-endian values of a compiled entry.
+type terminfoReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+var errTerminfoTruncated = errors.New("terminfo entry is truncated")
+
+func (r *terminfoReader) bytes(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n < 0 || r.pos+n > len(r.data) {
+		r.err = errTerminfoTruncated
+		return nil
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b
+}
+
+func (r *terminfoReader) short() int {
+	b := r.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(int16(binary.LittleEndian.Uint16(b)))
+}
+
+func (r *terminfoReader) shorts(n int) []int {
+	vs := make([]int, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		vs = append(vs, r.short())
+	}
+	return vs
+}
+
+// numbers reads n numbers that are either 16 or 32-bit wide depending on the storage format.
+func (r *terminfoReader) numbers(n int, wide bool) []int {
+	if !wide {
+		return r.shorts(n)
+	}
+	vs := make([]int, 0, n)
+	for i := 0; i < n && r.err == nil; i++ {
+		b := r.bytes(4)
+		if b == nil {
+			break
+		}
+		vs = append(vs, int(int32(binary.LittleEndian.Uint32(b))))
+	}
+	return vs
+}
+
+// align skips the padding byte inserted to keep the next section on an even offset.
+func (r *terminfoReader) align() {
+	if r.pos%2 == 1 && r.pos < len(r.data) {
+		r.pos++
+	}
+}
+
+// checkHeader returns an error if the header couldn't be read or contains negative sizes.
+func checkHeader(header []int, err error) error {
+	if err != nil {
+		return err
+	}
+	for _, v := range header {
+		if v < 0 {
+			return fmt.Errorf("invalid size %d in terminfo header", v)
+		}
+	}
+	return nil
+}
+
+// cstring returns the NUL-terminated string at offset in table.
+func cstring(table []byte, offset int) (string, bool) {
+	if offset < 0 || offset >= len(table) {
+		return "", false
+	}
+	end := offset
+	for end < len(table) && table[end] != 0 {
+		end++
+	}
+	return string(table[offset:end]), true
+}
+
+// parseTerminfo parses a compiled terminfo entry in the legacy or 32-bit storage format, including the extended
+// capabilities section if present.
+func parseTerminfo(data []byte) (*terminfo, error) {
+	r := &terminfoReader{data: data}
+	magic := r.short()
+	if r.err != nil {
+		return nil, r.err
+	}
+	var wide bool
+	switch magic {
+	case terminfoMagic:
+	case terminfoMagic32:
+		wide = true
+	default:
+		return nil, fmt.Errorf("unknown terminfo magic number %#o", magic)
+	}
+	header := r.shorts(5)
+	if err := checkHeader(header, r.err); err != nil {
+		return nil, err
+	}
+	namesSize, boolCount, numCount, strCount, tableSize := header[0], header[1], header[2], header[3], header[4]
+
+	ti := &terminfo{}
+	names := r.bytes(namesSize)
+	ti.names = strings.Split(strings.TrimRight(string(names), "\x00"), "|")
+	// termcolor doesn't use any of the predefined booleans.
+	r.bytes(boolCount)
+	r.align()
+	ti.numbers = r.numbers(numCount, wide)
+	offsets := r.shorts(strCount)
+	table := r.bytes(tableSize)
+	if r.err != nil {
+		return nil, r.err
+	}
+	ti.strings = make([]string, len(offsets))
+	for i, off := range offsets {
+		ti.strings[i], _ = cstring(table, off)
+	}
+
+	r.align()
+	if r.pos >= len(data) {
+		// No extended capabilities.
+		return ti, nil
+	}
+	if err := ti.parseExtended(r, wide); err != nil {
+		return nil, err
+	}
+	return ti, nil
+}
+
+// parseExtended parses the user-defined capabilities that follow the predefined ones.
+// The string table holds the values of the extended strings followed by the names of all extended capabilities.
+func (ti *terminfo) parseExtended(r *terminfoReader, wide bool) error {
+	header := r.shorts(5)
+	if err := checkHeader(header, r.err); err != nil {
+		return err
+	}
+	boolCount, numCount, strCount, tableSize := header[0], header[1], header[2], header[4]
+
+	bools := r.bytes(boolCount)
+	r.align()
+	numbers := r.numbers(numCount, wide)
+	offsets := r.shorts(strCount)
+	nameOffsets := r.shorts(boolCount + numCount + strCount)
+	table := r.bytes(tableSize)
+	if r.err != nil {
+		return r.err
+	}
+
+	values := make([]string, strCount)
+	namesStart := 0
+	for i, off := range offsets {
+		v, ok := cstring(table, off)
+		if !ok {
+			continue
+		}
+		values[i] = v
+		if end := off + len(v) + 1; end > namesStart {
+			namesStart = end
+		}
+	}
+	if namesStart > len(table) {
+		return errTerminfoTruncated
+	}
+	names := make([]string, len(nameOffsets))
+	for i, off := range nameOffsets {
+		name, ok := cstring(table[namesStart:], off)
+		if !ok {
+			return fmt.Errorf("invalid name offset %d in extended terminfo capabilities", off)
+		}
+		names[i] = name
+	}
+
+	ti.extBools = make(map[string]bool, boolCount)
+	for i, b := range bools {
+		ti.extBools[names[i]] = b == 1
+	}
+	ti.extNumbers = make(map[string]int, numCount)
+	for i, n := range numbers {
+		if n < 0 {
+			// Absent or cancelled.
+			continue
+		}
+		ti.extNumbers[names[boolCount+i]] = n
+	}
+	ti.extStrings = make(map[string]string, strCount)
+	for i, off := range offsets {
+		if off < 0 {
+			continue
+		}
+		ti.extStrings[names[boolCount+numCount+i]] = values[i]
+	}
+	return nil
+}
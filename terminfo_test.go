@@ -0,0 +1,235 @@
+package termcolor
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTerminfo(t *testing.T) {
+	testCases := map[string]struct {
+		path string
+
+		wantedNames  []string
+		wantedColors int
+		wantedLevel  Level
+		wantedBools  map[string]bool
+		wantedStrs   map[string]string
+	}{
+		"legacy format without extended capabilities": {
+			path:         "testdata/terminfo/t/termcolor-8",
+			wantedNames:  []string{"termcolor-8", "8 color terminal"},
+			wantedColors: 8,
+			wantedLevel:  LevelBasic,
+			wantedStrs: map[string]string{
+				"setaf": "\x1b[3%p1%dm",
+			},
+		},
+		"legacy format with extended capabilities": {
+			path:         "testdata/terminfo/t/termcolor-tc",
+			wantedNames:  []string{"termcolor-tc", "256 color terminal with the tmux Tc extension"},
+			wantedColors: 256,
+			wantedLevel:  Level16M,
+			wantedBools: map[string]bool{
+				"Tc": true,
+			},
+			wantedStrs: map[string]string{
+				"setaf": "\x1b[38;5;%p1%dm",
+				"sitm":  "\x1b[3m",
+				"Smulx": "\x1b[4:%p1%dm",
+			},
+		},
+		"32-bit format with extended capabilities": {
+			path:         "testdata/terminfo/t/termcolor-direct",
+			wantedNames:  []string{"termcolor-direct", "direct color terminal using the extended number format"},
+			wantedColors: 1 << 24,
+			wantedLevel:  Level16M,
+			wantedBools: map[string]bool{
+				"RGB": true,
+			},
+		},
+		"256 colors": {
+			path:         "testdata/terminfo/t/termcolor-256",
+			wantedNames:  []string{"termcolor-256", "256 color terminal"},
+			wantedColors: 256,
+			wantedLevel:  Level256,
+		},
+		"no colors": {
+			path:        "testdata/terminfo/t/termcolor-mono",
+			wantedNames: []string{"termcolor-mono", "terminal without colors"},
+			wantedLevel: LevelNone,
+		},
+		"ncurses linux entry": {
+			path:         "testdata/terminfo/l/linux",
+			wantedNames:  []string{"linux", "Linux console"},
+			wantedColors: 8,
+			wantedLevel:  LevelBasic,
+		},
+		"ncurses xterm entry": {
+			path:         "testdata/terminfo/x/xterm",
+			wantedNames:  []string{"xterm", "xterm-debian", "xterm terminal emulator (X Window System)"},
+			wantedColors: 8,
+			wantedLevel:  LevelBasic,
+		},
+		"ncurses screen entry": {
+			path:         "testdata/terminfo/s/screen",
+			wantedNames:  []string{"screen", "VT 100/ANSI X3.64 virtual terminal"},
+			wantedColors: 8,
+			wantedLevel:  LevelBasic,
+		},
+		"ncurses xterm-direct entry": {
+			path:         "testdata/terminfo/x/xterm-direct",
+			wantedNames:  []string{"xterm-direct", "xterm with direct-color indexing"},
+			wantedColors: 1 << 24,
+			wantedLevel:  Level16M,
+			wantedBools: map[string]bool{
+				"RGB": true,
+			},
+			wantedStrs: map[string]string{
+				"sitm": "\x1b[3m",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			ti, err := loadTerminfo(tc.path)
+
+			// Then
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if !reflect.DeepEqual(ti.names, tc.wantedNames) {
+				t.Errorf("expected names %q, got %q", tc.wantedNames, ti.names)
+			}
+			if colors, _ := ti.number("colors"); colors != tc.wantedColors {
+				t.Errorf("expected %d colors, got %d", tc.wantedColors, colors)
+			}
+			if l := ti.level(); l != tc.wantedLevel {
+				t.Errorf("expected level %v, got %v", tc.wantedLevel, l)
+			}
+			for name, wanted := range tc.wantedBools {
+				if got := ti.flag(name); got != wanted {
+					t.Errorf("expected %s to be %v, got %v", name, wanted, got)
+				}
+			}
+			for name, wanted := range tc.wantedStrs {
+				if got, _ := ti.str(name); got != wanted {
+					t.Errorf("expected %s to be %q, got %q", name, wanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTerminfo_Invalid(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/terminfo/t/termcolor-tc")
+	if err != nil {
+		t.Fatalf("read testdata: %v", err)
+	}
+	testCases := map[string][]byte{
+		"empty":             nil,
+		"unknown magic":     {0x1, 0x2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+		"negative size":     {0x1a, 0x1, 0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0},
+		"truncated header":  data[:6],
+		"truncated strings": data[:len(data)/2],
+		"truncated names":   data[:len(data)-3],
+	}
+
+	for name, data := range testCases {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			_, err := parseTerminfo(data)
+
+			// Then
+			if err == nil {
+				t.Errorf("expected an error")
+			}
+		})
+	}
+}
+
+func TestTerminfoDirs(t *testing.T) {
+	// Given
+	env := map[string]string{
+		"TERMINFO":      "/custom",
+		"HOME":          "/home/efe",
+		"TERMINFO_DIRS": "/first::/last",
+	}
+	getenv := func(key string) string {
+		return env[key]
+	}
+
+	// When
+	dirs := terminfoDirs(getenv, []string{"/usr/share/terminfo"})
+
+	// Then
+	wanted := []string{
+		"/custom",
+		filepath.Join("/home/efe", ".terminfo"),
+		"/first",
+		"/usr/share/terminfo",
+		"/last",
+		"/usr/share/terminfo",
+	}
+	if !reflect.DeepEqual(dirs, wanted) {
+		t.Errorf("expected %v, got %v", wanted, dirs)
+	}
+}
+
+func TestFindTerminfo(t *testing.T) {
+	testCases := map[string]struct {
+		term string
+		dirs []string
+
+		wantedPath string
+		wantedErr  error
+	}{
+		"first letter directory": {
+			term:       "termcolor-256",
+			dirs:       []string{"testdata/missing", "testdata/terminfo"},
+			wantedPath: filepath.Join("testdata", "terminfo", "t", "termcolor-256"),
+		},
+		"hexadecimal directory": {
+			term:       "termcolor-hex",
+			dirs:       []string{"testdata/terminfo"},
+			wantedPath: filepath.Join("testdata", "terminfo", "74", "termcolor-hex"),
+		},
+		"missing entry": {
+			term:      "termcolor-missing",
+			dirs:      []string{"testdata/terminfo"},
+			wantedErr: errTerminfoNotFound,
+		},
+		"path traversal": {
+			term:      "../terminfo/t/termcolor-256",
+			dirs:      []string{"testdata/terminfo"},
+			wantedErr: errTerminfoNotFound,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			path, err := findTerminfo(tc.term, tc.dirs)
+
+			// Then
+			if err != tc.wantedErr {
+				t.Fatalf("expected error %v, got %v", tc.wantedErr, err)
+			}
+			if path != tc.wantedPath {
+				t.Errorf("expected path %s, got %s", tc.wantedPath, path)
+			}
+		})
+	}
+}
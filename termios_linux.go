@@ -0,0 +1,10 @@
+// +build linux
+
+package termcolor
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlReadTermios  = unix.TCGETS
+	ioctlWriteTermios = unix.TCSETS
+)
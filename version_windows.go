@@ -11,7 +11,7 @@ import (
 // lookupWindows returns the level of the windows terminal. If the OS is windows, the terminal level is returned and
 // the boolean is set to true. If an error occurs then LevelBasic and true is returned.
 // If the OS is not windows, then LevelNone and false is returned.
-func windowsLevel() (Level, bool) {
+func lookupWindows() (Level, bool) {
 	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SOFTWARE\Microsoft\Windows NT\CurrentVersion`, registry.QUERY_VALUE)
 	if err != nil {
 		return LevelBasic, true
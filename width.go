@@ -0,0 +1,433 @@
+package termcolor
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/efekarakus/termcolor/ansi"
+)
+
+// Width returns the number of columns that s occupies in a terminal.
+// Escape sequences and control characters don't take any space, including tabs since their width depends on the
+// column they're written at: expand them first if needed. East Asian wide characters and emojis take two
+// columns, and combining marks and the characters joined to an emoji with a zero width joiner are part of the
+// previous character.
+func Width(s string) int {
+	var w int
+	for _, c := range cells(s) {
+		w += c.width
+	}
+	return w
+}
+
+// Truncate cuts s so that it occupies at most width columns, including the tail appended after the cut.
+// Escape sequences before the cut are kept and the styles and hyperlink left open at the cut are closed after
+// the tail. If s already fits, then it's returned as is. If width isn't positive, then returns an empty string.
+func Truncate(s string, width int, tail string) string {
+	if width <= 0 {
+		return ""
+	}
+	if Width(s) <= width {
+		return s
+	}
+	limit := width - Width(tail)
+	if limit < 0 {
+		return Truncate(tail, width, "")
+	}
+	var (
+		b     strings.Builder
+		state sgrState
+		link  bool
+		w     int
+	)
+	for _, c := range cells(s) {
+		if c.token != nil {
+			state.apply(c.token)
+			if uri, ok := hyperlink(c.token); ok {
+				link = uri != ""
+			}
+			b.WriteString(c.s)
+			continue
+		}
+		if w+c.width > limit {
+			break
+		}
+		b.WriteString(c.s)
+		w += c.width
+	}
+	b.WriteString(tail)
+	b.WriteString(state.close())
+	if link {
+		b.WriteString("\x1b]8;;\x1b\\")
+	}
+	return b.String()
+}
+
+// Wrap breaks the lines of s so that they occupy at most width columns.
+// Lines are broken at spaces when possible and words longer than width are broken at the last character that
+// fits. Styles and hyperlinks are closed at the end of every line, including the ones broken by s itself, and
+// opened again at the start of the next one, so that backgrounds don't bleed into the margin.
+func Wrap(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	w := wrapper{width: width}
+	for _, c := range cells(s) {
+		switch {
+		case c.s == "\n":
+			w.flushWord()
+			w.flushSpaces()
+			w.breakLine()
+		case c.s == " ":
+			w.flushWord()
+			w.spaces = append(w.spaces, c)
+			w.spacesWidth += c.width
+		default:
+			w.word = append(w.word, c)
+			w.wordWidth += c.width
+		}
+	}
+	w.flushWord()
+	w.flushSpaces()
+	return w.b.String()
+}
+
+// wrapper holds the state of Wrap.
+type wrapper struct {
+	width int
+
+	b         strings.Builder
+	state     sgrState
+	lineWidth int
+	// link is the sequence that opened the current hyperlink, or "" if there is none.
+	link string
+
+	// word holds the cells of the word being read, and spaces the spaces before it.
+	word        []cell
+	wordWidth   int
+	spaces      []cell
+	spacesWidth int
+}
+
+// flushWord writes the pending spaces and word on the current line, or on a new line if they don't fit.
+func (w *wrapper) flushWord() {
+	if len(w.word) == 0 {
+		return
+	}
+	switch {
+	case w.lineWidth+w.spacesWidth+w.wordWidth <= w.width:
+		w.commit(w.spaces)
+		w.commit(w.word)
+	case w.wordWidth <= w.width:
+		w.breakLine()
+		w.commit(w.word)
+	default:
+		if w.lineWidth+w.spacesWidth < w.width {
+			w.commit(w.spaces)
+		} else {
+			w.breakLine()
+		}
+		for _, c := range w.word {
+			if w.lineWidth > 0 && w.lineWidth+c.width > w.width {
+				w.breakLine()
+			}
+			w.commit([]cell{c})
+		}
+	}
+	w.word, w.wordWidth = nil, 0
+	w.spaces, w.spacesWidth = nil, 0
+}
+
+// flushSpaces writes the pending spaces at the end of the line if they fit, or drops them otherwise.
+func (w *wrapper) flushSpaces() {
+	if w.lineWidth+w.spacesWidth <= w.width {
+		w.commit(w.spaces)
+	}
+	w.spaces, w.spacesWidth = nil, 0
+}
+
+// breakLine closes the styles and hyperlink of the line, starts a new one and opens them again.
+func (w *wrapper) breakLine() {
+	w.b.WriteString(w.state.close())
+	if w.link != "" {
+		w.b.WriteString("\x1b]8;;\x1b\\")
+	}
+	w.b.WriteString("\n")
+	w.b.WriteString(w.link)
+	w.b.WriteString(w.state.open())
+	w.lineWidth = 0
+}
+
+func (w *wrapper) commit(cells []cell) {
+	for _, c := range cells {
+		if c.token != nil {
+			w.state.apply(c.token)
+			if uri, ok := hyperlink(c.token); ok {
+				w.link = ""
+				if uri != "" {
+					w.link = c.s
+				}
+			}
+		}
+		w.b.WriteString(c.s)
+		w.lineWidth += c.width
+	}
+}
+
+// cell is a grapheme cluster or an escape sequence.
+type cell struct {
+	s     string
+	width int
+	// token is the escape sequence of the cell, or nil for text.
+	token *ansi.Token
+}
+
+// cells splits s into grapheme clusters and escape sequences.
+func cells(s string) []cell {
+	var cs []cell
+	for _, tok := range ansi.Tokenize([]byte(s)) {
+		tok := tok
+		switch tok.Kind {
+		case ansi.Text:
+			text := string(tok.Raw)
+			for len(text) > 0 {
+				n, w := cluster(text)
+				cs = append(cs, cell{s: text[:n], width: w})
+				text = text[n:]
+			}
+		case ansi.Control:
+			cs = append(cs, cell{s: string(tok.Raw)})
+		default:
+			cs = append(cs, cell{s: string(tok.Raw), token: &tok})
+		}
+	}
+	return cs
+}
+
+// Characters that change how a grapheme cluster is displayed.
+const (
+	zeroWidthJoiner     = '\u200d'
+	emojiPresentation   = '\ufe0f'
+	regionalIndicatorLo = '\U0001f1e6'
+	regionalIndicatorHi = '\U0001f1ff'
+)
+
+// cluster returns the length in bytes and the width of the grapheme cluster at the start of s.
+// It's a simplification of the Unicode segmentation rules that handles combining marks, variation selectors,
+// emoji modifiers and tags, zero width joiner sequences and flags.
+func cluster(s string) (n, width int) {
+	base, size := utf8.DecodeRuneInString(s)
+	n, width = size, runeWidth(base)
+	isFlag := base >= regionalIndicatorLo && base <= regionalIndicatorHi
+	for n < len(s) {
+		r, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isFlag && r >= regionalIndicatorLo && r <= regionalIndicatorHi:
+			// A pair of regional indicators is a flag.
+			isFlag = false
+			width = 2
+		case r == zeroWidthJoiner:
+			n += size
+			if n < len(s) {
+				// The joined character is displayed as part of the sequence.
+				_, size = utf8.DecodeRuneInString(s[n:])
+			} else {
+				size = 0
+			}
+		case r == emojiPresentation:
+			if width == 1 {
+				width = 2
+			}
+		case isExtend(r):
+		default:
+			return n, width
+		}
+		n += size
+	}
+	return n, width
+}
+
+// isExtend returns true if r is displayed as part of the previous character.
+func isExtend(r rune) bool {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true
+	case r >= '\ufe00' && r <= '\ufe0f', r >= '\U000e0100' && r <= '\U000e01ef':
+		// Variation selectors.
+		return true
+	case r >= '\U0001f3fb' && r <= '\U0001f3ff':
+		// Emoji skin tone modifiers.
+		return true
+	case r >= '\U000e0020' && r <= '\U000e007f':
+		// Emoji tags.
+		return true
+	case r >= '\u1160' && r <= '\u11ff', r >= '\ud7b0' && r <= '\ud7ff':
+		// Hangul vowels and final consonants that combine with the previous syllable.
+		return true
+	}
+	return false
+}
+
+// runeWidth returns the number of columns of a single character.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20, r >= 0x7f && r < 0xa0:
+		return 0
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf), r >= '\u1160' && r <= '\u11ff':
+		return 0
+	case unicode.Is(wideTable, r):
+		return 2
+	}
+	return 1
+}
+
+// hyperlink returns the URI of an OSC 8 hyperlink token, which is empty if the token closes the hyperlink.
+// If the token isn't a hyperlink, then returns false.
+func hyperlink(tok *ansi.Token) (string, bool) {
+	if tok.Kind != ansi.OSC {
+		return "", false
+	}
+	parts := strings.SplitN(string(tok.Data), ";", 3)
+	if len(parts) != 3 || parts[0] != "8" {
+		return "", false
+	}
+	return parts[2], true
+}
+
+// sgrGroup is a set of SGR parameters that override each other, such as the foreground colors.
+type sgrGroup int
+
+const (
+	groupBold sgrGroup = iota
+	groupDim
+	groupItalic
+	groupUnderline
+	groupBlink
+	groupInverse
+	groupHidden
+	groupStrikethrough
+	groupForeground
+	groupBackground
+	groupOverline
+	groupUnderlineColor
+	numGroups
+)
+
+// sgrOff holds the SGR parameter that turns off each group.
+var sgrOff = [numGroups]string{"22", "22", "23", "24", "25", "27", "28", "29", "39", "49", "55", "59"}
+
+// sgrState tracks the graphic rendition set by SGR sequences.
+// It holds the parameters that turned on each group, or "" if the group is off.
+type sgrState [numGroups]string
+
+// apply updates the state with the parameters of an SGR sequence. Other tokens are ignored.
+func (s *sgrState) apply(tok *ansi.Token) {
+	if tok.Kind != ansi.CSI || tok.Final != 'm' || tok.Prefix != 0 || len(tok.Intermediates) != 0 {
+		return
+	}
+	if len(tok.Params) == 0 {
+		*s = sgrState{}
+		return
+	}
+	for i := 0; i < len(tok.Params); i++ {
+		p := tok.Params[i]
+		on := p.String()
+		switch code := p.Value(0); {
+		case code == 0:
+			*s = sgrState{}
+		case code == 1:
+			s[groupBold] = on
+		case code == 2:
+			s[groupDim] = on
+		case code == 3:
+			s[groupItalic] = on
+		case code == 4 && len(p) > 1 && p[1] == 0, code == 24:
+			s[groupUnderline] = ""
+		case code == 4, code == 21:
+			s[groupUnderline] = on
+		case code == 5, code == 6:
+			s[groupBlink] = on
+		case code == 7:
+			s[groupInverse] = on
+		case code == 8:
+			s[groupHidden] = on
+		case code == 9:
+			s[groupStrikethrough] = on
+		case code == 22:
+			s[groupBold], s[groupDim] = "", ""
+		case code == 23:
+			s[groupItalic] = ""
+		case code == 25:
+			s[groupBlink] = ""
+		case code == 27:
+			s[groupInverse] = ""
+		case code == 28:
+			s[groupHidden] = ""
+		case code == 29:
+			s[groupStrikethrough] = ""
+		case code >= 30 && code <= 37, code >= 90 && code <= 97:
+			s[groupForeground] = on
+		case code == 39:
+			s[groupForeground] = ""
+		case code >= 40 && code <= 47, code >= 100 && code <= 107:
+			s[groupBackground] = on
+		case code == 49:
+			s[groupBackground] = ""
+		case code == 53:
+			s[groupOverline] = on
+		case code == 55:
+			s[groupOverline] = ""
+		case code == 59:
+			s[groupUnderlineColor] = ""
+		case code == 38, code == 48, code == 58:
+			if len(p) == 1 {
+				// Semicolon separated form such as "38;5;118".
+				_, n := parseExtendedColorParams(tok.Params[i+1:])
+				for _, next := range tok.Params[i+1 : i+1+n] {
+					on += ";" + next.String()
+				}
+				i += n
+			}
+			switch code {
+			case 38:
+				s[groupForeground] = on
+			case 48:
+				s[groupBackground] = on
+			default:
+				s[groupUnderlineColor] = on
+			}
+		}
+	}
+}
+
+// open returns the SGR sequence that sets the state, or "" if every group is off.
+func (s *sgrState) open() string {
+	var on []string
+	for _, p := range s {
+		if p != "" {
+			on = append(on, p)
+		}
+	}
+	if len(on) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(on, ";") + "m"
+}
+
+// close returns the SGR sequence that turns off the groups that are on, or "" if every group is off.
+func (s *sgrState) close() string {
+	var off []string
+	for g, p := range s {
+		if p == "" {
+			continue
+		}
+		if len(off) == 0 || off[len(off)-1] != sgrOff[g] {
+			off = append(off, sgrOff[g])
+		}
+	}
+	if len(off) == 0 {
+		return ""
+	}
+	return "\x1b[" + strings.Join(off, ";") + "m"
+}
@@ -0,0 +1,202 @@
+package termcolor
+
+import (
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	testCases := map[string]struct {
+		s      string
+		wanted int
+	}{
+		"empty":                {s: "", wanted: 0},
+		"ascii":                {s: "hello", wanted: 5},
+		"escape sequences":     {s: "\x1b[1;38;5;118mhello\x1b[0m", wanted: 5},
+		"hyperlink":            {s: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", wanted: 4},
+		"control characters":   {s: "a\tb\r", wanted: 2},
+		"east asian wide":      {s: "日本語", wanted: 6},
+		"fullwidth forms":      {s: "ＡＢ", wanted: 4},
+		"combining marks":      {s: "e\u0301a\u0308", wanted: 2},
+		"emoji":                {s: "\U0001f600", wanted: 2},
+		"emoji presentation":   {s: "\u2764\ufe0f", wanted: 2},
+		"text presentation":    {s: "❤", wanted: 1},
+		"skin tone modifier":   {s: "\U0001f44d\U0001f3fd", wanted: 2},
+		"zero width joiner":    {s: "\U0001f468\u200d\U0001f469\u200d\U0001f467", wanted: 2},
+		"flag":                 {s: "\U0001f1eb\U0001f1f7", wanted: 2},
+		"hangul jamo":          {s: "\u1100\u1161\u11a8", wanted: 2},
+		"zero width space":     {s: "a\u200bb", wanted: 2},
+		"mixed text and emoji": {s: "ok \x1b[32m✅\x1b[0m", wanted: 5},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := Width(tc.s); got != tc.wanted {
+				t.Errorf("expected %d, got %d", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	testCases := map[string]struct {
+		s     string
+		width int
+		tail  string
+
+		wanted string
+	}{
+		"fits": {
+			s:      "\x1b[31mhello\x1b[0m",
+			width:  5,
+			tail:   "…",
+			wanted: "\x1b[31mhello\x1b[0m",
+		},
+		"plain text": {
+			s:      "hello world",
+			width:  8,
+			tail:   "...",
+			wanted: "hello...",
+		},
+		"closes open styles": {
+			s:      "\x1b[1;38;5;118mhello\x1b[0m world",
+			width:  3,
+			tail:   "…",
+			wanted: "\x1b[1;38;5;118mhe…\x1b[22;39m",
+		},
+		"keeps closed styles closed": {
+			s:      "\x1b[31mhi\x1b[39m there",
+			width:  5,
+			tail:   "…",
+			wanted: "\x1b[31mhi\x1b[39m t…",
+		},
+		"wide characters are not split": {
+			s:      "日本語",
+			width:  4,
+			tail:   "…",
+			wanted: "日…",
+		},
+		"clusters are not split": {
+			s:      "e\u0301e\u0301e\u0301",
+			width:  2,
+			tail:   "",
+			wanted: "e\u0301e\u0301",
+		},
+		"closes hyperlinks": {
+			s:      "\x1b]8;;https://example.com\x1b\\a long link\x1b]8;;\x1b\\",
+			width:  4,
+			tail:   "…",
+			wanted: "\x1b]8;;https://example.com\x1b\\a l…\x1b]8;;\x1b\\",
+		},
+		"tail wider than width": {
+			s:      "hello",
+			width:  2,
+			tail:   "...",
+			wanted: "..",
+		},
+		"zero width": {
+			s:      "\x1b[31mhello\x1b[0m",
+			width:  0,
+			tail:   "…",
+			wanted: "",
+		},
+		"negative width": {
+			s:      "hello",
+			width:  -1,
+			tail:   "…",
+			wanted: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			got := Truncate(tc.s, tc.width, tc.tail)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	testCases := map[string]struct {
+		s     string
+		width int
+
+		wanted string
+	}{
+		"fits": {
+			s:      "hello world",
+			width:  11,
+			wanted: "hello world",
+		},
+		"breaks at spaces": {
+			s:      "the quick brown fox",
+			width:  10,
+			wanted: "the quick\nbrown fox",
+		},
+		"keeps existing newlines": {
+			s:      "a b\nc d",
+			width:  3,
+			wanted: "a b\nc d",
+		},
+		"breaks long words": {
+			s:      "abcdefgh ij",
+			width:  3,
+			wanted: "abc\ndef\ngh\nij",
+		},
+		"wide characters": {
+			s:      "日本語です",
+			width:  5,
+			wanted: "日本\n語で\nす",
+		},
+		"escape sequences take no space": {
+			s:      "\x1b[1mbold\x1b[22m text",
+			width:  9,
+			wanted: "\x1b[1mbold\x1b[22m text",
+		},
+		"styles are closed and opened again": {
+			s:      "\x1b[38;5;118;44mhello world\x1b[0m",
+			width:  5,
+			wanted: "\x1b[38;5;118;44mhello\x1b[39;49m\n\x1b[38;5;118;44mworld\x1b[0m",
+		},
+		"styles are closed and opened again at line breaks": {
+			s:      "\x1b[44mhi\nthere\x1b[49m",
+			width:  10,
+			wanted: "\x1b[44mhi\x1b[49m\n\x1b[44mthere\x1b[49m",
+		},
+		"hyperlinks are closed and opened again": {
+			s:      "\x1b]8;;https://example.com\x1b\\a link\x1b]8;;\x1b\\ b",
+			width:  4,
+			wanted: "\x1b]8;;https://example.com\x1b\\a\x1b]8;;\x1b\\\n\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\\nb",
+		},
+		"zero width": {
+			s:      "hello world",
+			width:  0,
+			wanted: "hello world",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// When
+			got := Wrap(tc.s, tc.width)
+
+			// Then
+			if got != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package termcolor
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/efekarakus/termcolor/ansi"
+)
+
+// File is a writer with a file descriptor, such as *os.File.
+type File interface {
+	io.Writer
+	FileDescriptor
+}
+
+// Writer is an io.Writer that adapts the SGR escape sequences written to it to a color level.
+//
+// At LevelNone, all SGR sequences are removed. At lower levels, 24-bit and 256 colors are replaced with the
+// closest colors available at the level. Text and other escape sequences are written as is.
+// Sequences split across calls to Write are reassembled before being rewritten.
+type Writer struct {
+	w     io.Writer
+	level Level
+
+	tokenizer ansi.Tokenizer
+	// out holds the output of a single call to Write.
+	out []byte
+}
+
+// NewWriter returns a Writer that adapts the colors written to w to the level.
+func NewWriter(w io.Writer, l Level) *Writer {
+	return &Writer{
+		w:     w,
+		level: l,
+	}
+}
+
+// NewAutoWriter returns a Writer that adapts the colors written to f to the number of colors supported by f, so
+// that bright colors are rendered with the normal ones on the Linux console for example.
+func NewAutoWriter(f File) *Writer {
+	return NewWriter(f, ColorsLevel(Colors(f)))
+}
+
+// Level returns the color level that the writer adapts escape sequences to.
+func (w *Writer) Level() Level {
+	return w.level
+}
+
+// Unwrap returns the underlying writer.
+func (w *Writer) Unwrap() io.Writer {
+	return w.w
+}
+
+// Write writes p to the underlying writer after rewriting its SGR sequences.
+// A control sequence at the end of p that isn't complete yet is held until the next call to Write or Flush.
+// Sequences that are too long to be complete, such as an unterminated OSC string, are written as is once they
+// exceed 64 KiB, along with the text that follows them.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.level.AtLeast(Level16M) {
+		// There is nothing to rewrite.
+		return w.w.Write(p)
+	}
+	w.out = w.out[:0]
+	for _, tok := range w.tokenizer.Feed(p) {
+		w.out = append(w.out, w.rewrite(tok)...)
+	}
+	if len(w.out) == 0 {
+		return len(p), nil
+	}
+	if _, err := w.w.Write(w.out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any incomplete control sequence held by the writer as is.
+func (w *Writer) Flush() error {
+	w.out = w.out[:0]
+	for _, tok := range w.tokenizer.Flush() {
+		w.out = append(w.out, tok.Raw...)
+	}
+	if len(w.out) == 0 {
+		return nil
+	}
+	_, err := w.w.Write(w.out)
+	return err
+}
+
+// rewrite returns the token adapted to the level of the writer.
+// Tokens other than SGR sequences are returned as is.
+func (w *Writer) rewrite(tok ansi.Token) []byte {
+	if tok.Kind != ansi.CSI || tok.Final != 'm' || tok.Prefix != 0 || len(tok.Intermediates) != 0 {
+		return tok.Raw
+	}
+	if w.level == LevelNone {
+		return nil
+	}
+	rewritten, ok := rewriteSGR(tok.Params, w.level)
+	if !ok {
+		// Every parameter was dropped, removing the sequence keeps the current rendition.
+		return nil
+	}
+	return []byte("\x1b[" + rewritten + "m")
+}
+
+// rewriteSGR returns the SGR parameters with colors replaced by the closest colors available at the level.
+// If all parameters were dropped, then returns false.
+func rewriteSGR(params []ansi.Param, l Level) (string, bool) {
+	if len(params) == 0 {
+		// Reset.
+		return "", true
+	}
+	out := make([]string, 0, len(params))
+	for i := 0; i < len(params); i++ {
+		code := params[i].Value(0)
+		if code != 38 && code != 48 && code != 58 {
+			if !l.AtLeast(LevelBasic) && len(params[i]) == 1 && (code >= 90 && code <= 97 || code >= 100 && code <= 107) {
+				// Render aixterm's bright colors with the normal ones.
+				out = append(out, strconv.Itoa(code-60))
+				continue
+			}
+			out = append(out, params[i].String())
+			continue
+		}
+		var (
+			c Color
+			n int
+		)
+		if len(params[i]) > 1 {
+			c = parseExtendedColor(params[i][1:])
+		} else {
+			c, n = parseExtendedColorParams(params[i+1:])
+		}
+		if c == nil {
+			// Leave unknown color formats untouched.
+			out = append(out, params[i].String())
+			continue
+		}
+		i += n
+		if p := extendedColorSGR(code, l.Convert(c), l); p != "" {
+			out = append(out, p)
+		}
+	}
+	if len(out) == 0 {
+		return "", false
+	}
+	return strings.Join(out, ";"), true
+}
+
+// parseExtendedColor parses the colon separated subparameters of an extended color,
+// such as [5, 118] or [2, Default, 25, 255, 203] where the omitted value is the color space.
+func parseExtendedColor(sub []int) Color {
+	switch {
+	case sub[0] == 5 && len(sub) == 2:
+		return color256(sub[1])
+	case sub[0] == 2 && (len(sub) == 4 || len(sub) == 5):
+		return rgb(sub[len(sub)-3:])
+	default:
+		return nil
+	}
+}
+
+// parseExtendedColorParams parses the semicolon separated parameters following 38, 48 or 58,
+// such as [5, 118] or [2, 25, 255, 203], and returns the number of parameters used.
+func parseExtendedColorParams(params []ansi.Param) (Color, int) {
+	values := make([]int, 0, 4)
+	for _, p := range params {
+		if len(values) == 4 || len(p) != 1 {
+			break
+		}
+		values = append(values, p[0])
+	}
+	switch {
+	case len(values) >= 2 && values[0] == 5:
+		if c := color256(values[1]); c != nil {
+			return c, 2
+		}
+	case len(values) == 4 && values[0] == 2:
+		if c := rgb(values[1:]); c != nil {
+			return c, 4
+		}
+	}
+	return nil, 0
+}
+
+// color256 returns the color at index i of the 256 colors palette, or nil if i is out of range.
+func color256(i int) Color {
+	v, ok := parseComponent(i)
+	if !ok {
+		return nil
+	}
+	return Color256(v)
+}
+
+// rgb returns the 24-bit color with the red, green and blue components of values, or nil if one is out of range.
+func rgb(values []int) Color {
+	var c [3]uint8
+	for i := range c {
+		v, ok := parseComponent(values[i])
+		if !ok {
+			return nil
+		}
+		c[i] = v
+	}
+	return RGB{c[0], c[1], c[2]}
+}
+
+// parseComponent returns a color index or component between 0 and 255.
+func parseComponent(v int) (uint8, bool) {
+	if v < 0 || v > 255 {
+		return 0, false
+	}
+	return uint8(v), true
+}
+
+// extendedColorSGR returns the SGR parameters that select the color for the foreground (38), background (48) or
+// underline (58). Underline colors are dropped below Level256 since there is no basic form for them.
+func extendedColorSGR(code int, c Color, l Level) string {
+	if c == nil {
+		return ""
+	}
+	switch code {
+	case 38:
+		return c.sgr(l, false)
+	case 48:
+		return c.sgr(l, true)
+	}
+	switch c := c.(type) {
+	case RGB:
+		return "58;2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+	case Color256:
+		return "58;5;" + strconv.Itoa(int(c))
+	case Color88:
+		return "58;5;" + strconv.Itoa(int(c))
+	}
+	return ""
+}
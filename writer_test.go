@@ -0,0 +1,177 @@
+package termcolor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriter(t *testing.T) {
+	testCases := map[string]struct {
+		level  Level
+		writes []string
+
+		wanted string
+	}{
+		"true colors are written as is": {
+			level:  Level16M,
+			writes: []string{"\x1b[38;2;25;255;203mSuccess!\x1b[0m"},
+			wanted: "\x1b[38;2;25;255;203mSuccess!\x1b[0m",
+		},
+		"sgr sequences are stripped without colors": {
+			level:  LevelNone,
+			writes: []string{"\x1b[1;38;2;25;255;203mSuccess!\x1b[0m \x1b[mdone\x1b[K"},
+			wanted: "Success! done\x1b[K",
+		},
+		"true colors are downsampled to 256 colors": {
+			level:  Level256,
+			writes: []string{"\x1b[1;38;2;255;128;0;48;2;0;0;0mWarning\x1b[0m"},
+			wanted: "\x1b[1;38;5;208;48;5;16mWarning\x1b[0m",
+		},
+		"colon separated true colors are downsampled": {
+			level:  Level256,
+			writes: []string{"\x1b[38:2::255:128:0mWarning\x1b[39m"},
+			wanted: "\x1b[38;5;208mWarning\x1b[39m",
+		},
+		"256 colors are downsampled to 88 colors": {
+			level:  Level88,
+			writes: []string{"\x1b[38;5;196;58;5;196mError\x1b[0m"},
+			wanted: "\x1b[38;5;64;58;5;64mError\x1b[0m",
+		},
+		"bright colors are dropped at 8 colors": {
+			level:  Level8,
+			writes: []string{"\x1b[1;91;104mError\x1b[0m \x1b[38;5;12mhint\x1b[0m"},
+			wanted: "\x1b[1;31;44mError\x1b[0m \x1b[34mhint\x1b[0m",
+		},
+		"256 colors are kept at 256 colors": {
+			level:  Level256,
+			writes: []string{"\x1b[38;5;118mok\x1b[0m"},
+			wanted: "\x1b[38;5;118mok\x1b[0m",
+		},
+		"256 colors are downsampled to basic colors": {
+			level:  LevelBasic,
+			writes: []string{"\x1b[38;5;118;48:5:4mok\x1b[0m"},
+			wanted: "\x1b[92;44mok\x1b[0m",
+		},
+		"true colors are downsampled to basic colors": {
+			level:  LevelBasic,
+			writes: []string{"\x1b[48;2;135;255;0mok"},
+			wanted: "\x1b[102mok",
+		},
+		"underline colors are dropped below 256 colors": {
+			level:  LevelBasic,
+			writes: []string{"\x1b[58;2;255;0;0mok\x1b[4;58;5;1mok"},
+			wanted: "ok\x1b[4mok",
+		},
+		"underline colors are downsampled to 256 colors": {
+			level:  Level256,
+			writes: []string{"\x1b[58:2::255:0:0mok"},
+			wanted: "\x1b[58;5;196mok",
+		},
+		"sequences split across writes": {
+			level:  Level256,
+			writes: []string{"a\x1b", "[38;2;255;1", "28;0", "mb"},
+			wanted: "a\x1b[38;5;208mb",
+		},
+		"malformed colors are left untouched": {
+			level:  Level256,
+			writes: []string{"\x1b[38;2;300;0;0mok\x1b[38;5mok"},
+			wanted: "\x1b[38;2;300;0;0mok\x1b[38;5mok",
+		},
+		"other sequences are written as is": {
+			level: LevelNone,
+			writes: []string{
+				"\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\",
+				"\x1b]0;title\a\x1b[2J\x1b7\x1b[?25l",
+			},
+			wanted: "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\\x1b]0;title\a\x1b[2J\x1b7\x1b[?25l",
+		},
+		"strings are written as is": {
+			level:  LevelNone,
+			writes: []string{"\x1bPq#0;2;0;0;0\x1b\\\x1b_Gf=100;\x1b[31m"},
+			wanted: "\x1bPq#0;2;0;0;0\x1b\\\x1b_Gf=100;",
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			buf := new(bytes.Buffer)
+			w := NewWriter(buf, tc.level)
+
+			// When
+			for _, s := range tc.writes {
+				n, err := w.Write([]byte(s))
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if n != len(s) {
+					t.Fatalf("expected %d bytes written, got %d", len(s), n)
+				}
+			}
+
+			// Then
+			if got := buf.String(); got != tc.wanted {
+				t.Errorf("expected %q, got %q", tc.wanted, got)
+			}
+		})
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	// Given
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf, LevelNone)
+	w.Write([]byte("text\x1b[38;2"))
+
+	// When
+	err := w.Flush()
+
+	// Then
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if wanted := "text\x1b[38;2"; buf.String() != wanted {
+		t.Errorf("expected %q, got %q", wanted, buf.String())
+	}
+}
+
+func TestWriter_UnterminatedString(t *testing.T) {
+	// Given
+	buf := new(bytes.Buffer)
+	w := NewWriter(buf, LevelBasic)
+	w.Write([]byte("x\x1b]0;title"))
+	text := strings.Repeat("plain text\n", 10000)
+
+	// When
+	w.Write([]byte(text))
+	w.Write([]byte("\x1b[38;5;196mred"))
+
+	// Then
+	if wanted := "x\x1b]0;title" + text + "\x1b[91mred"; buf.String() != wanted {
+		t.Errorf("expected the %d bytes written after the unterminated string, got %d bytes", len(wanted), buf.Len())
+	}
+}
+
+type errWriter struct{}
+
+func (errWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("some error")
+}
+
+func TestWriter_Error(t *testing.T) {
+	// Given
+	w := NewWriter(errWriter{}, Level256)
+
+	// When
+	_, err := w.Write([]byte("text"))
+
+	// Then
+	if err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,70 @@
+package termcolor
+
+import (
+	"io"
+)
+
+// maxUnwrap is the number of writers that LevelFor unwraps before giving up, in case they wrap each other.
+const maxUnwrap = 32
+
+// noFd is the file descriptor of writers that aren't backed by a file, such as a bytes.Buffer.
+// It's never a terminal.
+const noFd = ^uintptr(0)
+
+// noFile is the file descriptor of writers that aren't backed by a file.
+type noFile struct{}
+
+func (noFile) Fd() uintptr {
+	return noFd
+}
+
+// LevelFor returns the color level that's supported by the writer. See Detector.LevelFor.
+func LevelFor(w io.Writer) Level {
+	return NewDetector().LevelFor(w)
+}
+
+// LevelFor returns the color level that's supported by the writer.
+// Writers that implement FileDescriptor, such as *os.File, are detected like with Level. Writers that wrap another
+// one and expose it with an "Unwrap() io.Writer" method are unwrapped first, and a *Writer reports its own level,
+// with Level8 and Level88 reported as LevelBasic like Level does.
+// Other writers, such as a bytes.Buffer or a bufio.Writer, aren't terminals: their level is LevelNone unless colors
+// are forced with a flag or an environment variable such as FORCE_COLOR.
+func (d *Detector) LevelFor(w io.Writer) Level {
+	if cw, ok := unwrapWriter(w).(*Writer); ok {
+		return cw.Level().standard()
+	}
+	return d.Level(writerFd(w))
+}
+
+// LevelFor returns the color level that's supported by the writer like Detector.LevelFor, but only detects the
+// level once per file descriptor. Writers that aren't backed by a file share the same cached level.
+func (c *CachedDetector) LevelFor(w io.Writer) Level {
+	if cw, ok := unwrapWriter(w).(*Writer); ok {
+		return cw.Level().standard()
+	}
+	return c.Level(writerFd(w))
+}
+
+// writerFd returns the file descriptor that backs the writer, or noFile if there is none.
+func writerFd(w io.Writer) FileDescriptor {
+	if f, ok := unwrapWriter(w).(FileDescriptor); ok {
+		return f
+	}
+	return noFile{}
+}
+
+// unwrapWriter returns the innermost writer that is a FileDescriptor or a *Writer, or w if there is none.
+func unwrapWriter(w io.Writer) io.Writer {
+	inner := w
+	for i := 0; i < maxUnwrap && inner != nil; i++ {
+		switch v := inner.(type) {
+		case FileDescriptor, *Writer:
+			return v
+		case interface{ Unwrap() io.Writer }:
+			inner = v.Unwrap()
+		default:
+			return w
+		}
+	}
+	return w
+}
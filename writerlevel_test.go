@@ -0,0 +1,136 @@
+package termcolor
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// unwrapper is a writer that exposes the writer it wraps.
+type unwrapper struct {
+	io.Writer
+}
+
+func (w unwrapper) Unwrap() io.Writer {
+	return w.Writer
+}
+
+func TestDetector_LevelFor(t *testing.T) {
+	testCases := map[string]struct {
+		env    map[string]string
+		mode   ColorMode
+		writer io.Writer
+
+		wantedLevel Level
+	}{
+		"file descriptor": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      os.Stdout,
+			wantedLevel: Level256,
+		},
+		"file descriptor that isn't a terminal": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      os.Stderr,
+			wantedLevel: LevelNone,
+		},
+		"wrapped file descriptor": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      unwrapper{unwrapper{os.Stdout}},
+			wantedLevel: Level256,
+		},
+		"in-memory writer": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      &bytes.Buffer{},
+			wantedLevel: LevelNone,
+		},
+		"wrapped in-memory writer": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      unwrapper{&bytes.Buffer{}},
+			wantedLevel: LevelNone,
+		},
+		"buffered file descriptor can't be unwrapped": {
+			env:         map[string]string{"TERM": "xterm-256color"},
+			writer:      bufio.NewWriter(os.Stdout),
+			wantedLevel: LevelNone,
+		},
+		"in-memory writer forced with FORCE_COLOR": {
+			env:         map[string]string{"FORCE_COLOR": "3"},
+			writer:      &bytes.Buffer{},
+			wantedLevel: Level16M,
+		},
+		"in-memory writer forced with a color mode": {
+			mode:        ColorModeAlways,
+			writer:      &bytes.Buffer{},
+			wantedLevel: LevelBasic,
+		},
+		"color writer": {
+			env:         map[string]string{"FORCE_COLOR": "3"},
+			writer:      NewWriter(&bytes.Buffer{}, Level8),
+			wantedLevel: LevelBasic,
+		},
+		"wrapped color writer": {
+			writer:      unwrapper{NewWriter(os.Stdout, Level88)},
+			wantedLevel: LevelBasic,
+		},
+	}
+
+	for name, tc := range testCases {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Given
+			mode := tc.mode
+			d := NewDetector(
+				WithArgs(nil),
+				WithColorMode(&mode),
+				WithEnv(tc.env),
+				WithTerminalChecker(func(fd uintptr) bool {
+					return fd == 1
+				}),
+				WithTerminfoDirs(),
+				WithCommandRunner(nil),
+			)
+
+			// When
+			l := d.LevelFor(tc.writer)
+
+			// Then
+			if l != tc.wantedLevel {
+				t.Errorf("expected %v, got %v", tc.wantedLevel, l)
+			}
+		})
+	}
+}
+
+func TestDetector_LevelForExplainsMissingFileDescriptor(t *testing.T) {
+	// Given
+	d := NewDetector(WithArgs(nil), WithEnv(map[string]string{"TERM": "xterm-256color"}), WithCommandRunner(nil))
+
+	// When
+	decision := d.Explain(noFile{})
+
+	// Then
+	if decision.Level != LevelNone {
+		t.Errorf("expected %v, got %v", LevelNone, decision.Level)
+	}
+	if got := decision.String(); !strings.Contains(got, "writer without file descriptor") {
+		t.Errorf("expected the decision to mention the missing file descriptor, got %q", got)
+	}
+}
+
+func TestCachedDetector_LevelFor(t *testing.T) {
+	// Given
+	c := NewCachedDetector(WithArgs(nil), WithEnv(map[string]string{"FORCE_COLOR": "2"}))
+
+	// When
+	l := c.LevelFor(unwrapper{&bytes.Buffer{}})
+
+	// Then
+	if l != Level256 {
+		t.Errorf("expected %v, got %v", Level256, l)
+	}
+}